@@ -1,17 +1,39 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"flag"
 	"fmt"
 	"os"
+	"strconv"
 
 	_ "github.com/lib/pq"
 	"github.com/pacahar/pr-reviewer-assignment/internal/config"
+	"github.com/pacahar/pr-reviewer-assignment/internal/migrator"
+	"github.com/pacahar/pr-reviewer-assignment/migrations"
 )
 
+const usage = `usage: migrate [command]
+
+commands:
+  up         apply all pending migrations (default)
+  down N     roll back the last N migrations
+  status     list migrations and whether they are applied
+  goto V     migrate up or down to version V`
+
 func main() {
 	config := config.MustLoad()
 
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+
+	args := flag.Args()
+	if len(args) == 0 {
+		args = []string{"up"}
+	}
+
 	db, err := sql.Open("postgres", config.Database.DSN())
 
 	if err != nil {
@@ -19,15 +41,70 @@ func main() {
 	}
 	defer db.Close()
 
-	data, err := os.ReadFile("migrations/init.sql")
+	m, err := migrator.New(db, migrations.FS)
 	if err != nil {
 		panic(err)
 	}
 
-	_, err = db.Exec(string(data))
+	ctx := context.Background()
+
+	switch {
+	case args[0] == "up" && len(args) == 1:
+		done, err := m.Up(ctx)
+		report("applied", done, err)
+
+	case args[0] == "down" && len(args) == 2:
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			exitUsage()
+		}
+
+		done, err := m.Down(ctx, n)
+		report("rolled back", done, err)
+
+	case args[0] == "goto" && len(args) == 2:
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || version < 0 {
+			exitUsage()
+		}
+
+		done, err := m.Goto(ctx, version)
+		report("migrated", done, err)
+
+	case args[0] == "status" && len(args) == 1:
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			panic(err)
+		}
+
+		for _, s := range statuses {
+			applied := "pending"
+			if s.Applied {
+				applied = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%06d  %-30s %s\n", s.Version, s.Name, applied)
+		}
+
+	default:
+		exitUsage()
+	}
+}
+
+func report(action string, done []migrator.Migration, err error) {
+	for _, mig := range done {
+		fmt.Printf("%s %06d_%s\n", action, mig.Version, mig.Name)
+	}
+
 	if err != nil {
 		panic(err)
 	}
 
-	fmt.Println("Migration applied!")
+	if len(done) == 0 {
+		fmt.Println("No migrations to run")
+	}
+}
+
+func exitUsage() {
+	fmt.Fprintln(os.Stderr, usage)
+	os.Exit(2)
 }
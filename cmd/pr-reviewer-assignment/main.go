@@ -6,10 +6,14 @@ import (
 	"os"
 	"strconv"
 
+	"github.com/pacahar/pr-reviewer-assignment/internal/assignment"
 	"github.com/pacahar/pr-reviewer-assignment/internal/config"
 	"github.com/pacahar/pr-reviewer-assignment/internal/constants"
 	handlers "github.com/pacahar/pr-reviewer-assignment/internal/http"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage/memory"
 	"github.com/pacahar/pr-reviewer-assignment/internal/storage/postgres"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage/sqlite"
 )
 
 func main() {
@@ -17,13 +21,19 @@ func main() {
 
 	log := setupLogger(config.Environment)
 
-	storage, err := postgres.NewPostgresStorage(config.Database.DSN())
+	storage, err := setupStorage(config.Database)
 	if err != nil {
 		log.Error("failed to initialize storage", slog.String("error", err.Error()))
 		return
 	}
 
-	h := handlers.NewHandler(storage, log)
+	selector, err := assignment.New(config.Assignment.Strategy)
+	if err != nil {
+		log.Error("failed to initialize reviewer selector", slog.String("error", err.Error()))
+		return
+	}
+
+	h := handlers.NewHandler(storage, selector, config.Merge.RequireApprovals, log)
 
 	mux := http.NewServeMux()
 	h.RegisterRoutes(mux)
@@ -40,6 +50,17 @@ func main() {
 	}
 }
 
+func setupStorage(db config.DB) (*storage.Storage, error) {
+	switch db.Driver {
+	case constants.DriverMemory:
+		return memory.NewMemoryStorage(), nil
+	case constants.DriverSQLite:
+		return sqlite.NewSQLiteStorage(db.Path)
+	default:
+		return postgres.NewPostgresStorage(db.DSN(), db.AutoMigrate)
+	}
+}
+
 func setupLogger(env string) *slog.Logger {
 	var log *slog.Logger
 
@@ -0,0 +1,14 @@
+package assignment
+
+import (
+	"context"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+)
+
+// FirstSelector takes candidates in the order storage returned them.
+type FirstSelector struct{}
+
+func (s *FirstSelector) Select(ctx context.Context, st *storage.Storage, req Request) (Selection, error) {
+	return Selection{Reviewers: limit(req.Candidates, req.Count)}, nil
+}
@@ -0,0 +1,36 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+)
+
+// LoadBalancedSelector prefers candidates with the fewest OPEN pull requests
+// under review. Ties are broken by user_id so the result is deterministic.
+type LoadBalancedSelector struct{}
+
+func (s *LoadBalancedSelector) Select(ctx context.Context, st *storage.Storage, req Request) (Selection, error) {
+	const op = "assignment.LoadBalancedSelector.Select"
+
+	counts, err := st.PullRequestStorage.GetOpenReviewCountsByTeam(ctx, req.TeamName)
+	if err != nil {
+		return Selection{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	candidates := make([]models.User, len(req.Candidates))
+	copy(candidates, req.Candidates)
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ci, cj := counts[candidates[i].UserID], counts[candidates[j].UserID]
+		if ci != cj {
+			return ci < cj
+		}
+		return candidates[i].UserID < candidates[j].UserID
+	})
+
+	return Selection{Reviewers: limit(candidates, req.Count)}, nil
+}
@@ -0,0 +1,54 @@
+package assignment
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+)
+
+// RandomSelector shuffles candidates with a fresh seed per selection. The seed
+// is returned with the selection, so the same candidates and seed always
+// produce the same reviewers.
+type RandomSelector struct {
+	Seed func() int64
+}
+
+func NewRandomSelector(seed func() int64) *RandomSelector {
+	if seed == nil {
+		seed = func() int64 { return time.Now().UnixNano() }
+	}
+
+	return &RandomSelector{Seed: seed}
+}
+
+func (s *RandomSelector) Select(ctx context.Context, st *storage.Storage, req Request) (Selection, error) {
+	seed := s.Seed()
+
+	return Selection{
+		Reviewers: limit(Shuffle(req.Candidates, seed), req.Count),
+		Seed:      &seed,
+	}, nil
+}
+
+// Shuffle returns a copy of users permuted deterministically by seed. Users
+// are ordered by id first, so the result does not depend on the order storage
+// returned them in.
+func Shuffle(users []models.User, seed int64) []models.User {
+	result := make([]models.User, len(users))
+	copy(result, users)
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UserID < result[j].UserID
+	})
+
+	rnd := rand.New(rand.NewSource(seed))
+	rnd.Shuffle(len(result), func(i, j int) {
+		result[i], result[j] = result[j], result[i]
+	})
+
+	return result
+}
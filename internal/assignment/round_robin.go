@@ -0,0 +1,44 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+)
+
+// RoundRobinSelector cycles through candidates ordered by user_id, using a
+// per-team cursor kept in storage.
+type RoundRobinSelector struct{}
+
+func (s *RoundRobinSelector) Select(ctx context.Context, st *storage.Storage, req Request) (Selection, error) {
+	const op = "assignment.RoundRobinSelector.Select"
+
+	count := min(req.Count, len(req.Candidates))
+	if count <= 0 {
+		return Selection{}, nil
+	}
+
+	candidates := make([]models.User, len(req.Candidates))
+	copy(candidates, req.Candidates)
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].UserID < candidates[j].UserID
+	})
+
+	position, err := st.TeamStorage.AdvanceRotation(ctx, req.TeamName, count)
+	if err != nil {
+		return Selection{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	start := int(position % int64(len(candidates)))
+
+	reviewers := make([]models.User, 0, count)
+	for i := 0; i < count; i++ {
+		reviewers = append(reviewers, candidates[(start+i)%len(candidates)])
+	}
+
+	return Selection{Reviewers: reviewers}, nil
+}
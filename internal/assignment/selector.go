@@ -0,0 +1,68 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+)
+
+const (
+	StrategyFirst        string = "first"
+	StrategyLoadBalanced string = "load_balanced"
+	StrategyRandom       string = "random"
+	StrategyRoundRobin   string = "round_robin"
+)
+
+// Request describes a single reviewer selection. Candidates are already
+// filtered: active, not the author and not assigned to the pull request.
+type Request struct {
+	PullRequestID string
+	AuthorID      string
+	TeamName      string
+	Candidates    []models.User
+	Count         int
+}
+
+// Selection is the outcome of a reviewer selection. Seed is set by
+// randomized strategies so the selection can be reproduced later.
+type Selection struct {
+	Reviewers []models.User
+	Seed      *int64
+}
+
+type ReviewerSelector interface {
+	Select(ctx context.Context, st *storage.Storage, req Request) (Selection, error)
+}
+
+func New(strategy string) (ReviewerSelector, error) {
+	const op = "assignment.New"
+
+	switch strategy {
+	case StrategyFirst, "":
+		return &FirstSelector{}, nil
+	case StrategyLoadBalanced:
+		return &LoadBalancedSelector{}, nil
+	case StrategyRandom:
+		return NewRandomSelector(nil), nil
+	case StrategyRoundRobin:
+		return &RoundRobinSelector{}, nil
+	default:
+		return nil, fmt.Errorf("%s: unknown strategy %q", op, strategy)
+	}
+}
+
+func limit(users []models.User, count int) []models.User {
+	if count < 0 {
+		count = 0
+	}
+	if len(users) > count {
+		users = users[:count]
+	}
+
+	result := make([]models.User, len(users))
+	copy(result, users)
+
+	return result
+}
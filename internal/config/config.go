@@ -7,12 +7,15 @@ import (
 	"os"
 
 	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/pacahar/pr-reviewer-assignment/internal/constants"
 )
 
 type Config struct {
 	Environment string     `yaml:"environment" env-required:"true"` // local, dev, production
 	HTTPServer  HTTPServer `yaml:"http_server"`
 	Database    DB         `yaml:"database"`
+	Assignment  Assignment `yaml:"assignment"`
+	Merge       Merge      `yaml:"merge"`
 }
 
 type HTTPServer struct {
@@ -21,11 +24,24 @@ type HTTPServer struct {
 }
 
 type DB struct {
-	Host     string `yaml:"host" env-required:"true"`
-	Port     int    `yaml:"port" env-required:"true"`
-	Username string `yaml:"username" env-required:"true"`
-	Password string `yaml:"password" env-required:"true"`
-	DBName   string `yaml:"db_name" env-default:"assignment"`
+	Driver      string `yaml:"driver" env-default:"postgres"` // postgres, memory, sqlite
+	Host        string `yaml:"host"`
+	Port        int    `yaml:"port"`
+	Username    string `yaml:"username"`
+	Password    string `yaml:"password"`
+	DBName      string `yaml:"db_name" env-default:"assignment"`
+	AutoMigrate bool   `yaml:"auto_migrate" env-default:"false"` // postgres only
+	Path        string `yaml:"path" env-default:"assignment.db"` // sqlite only
+}
+
+type Assignment struct {
+	Strategy string `yaml:"strategy" env-default:"first"` // first, load_balanced, random, round_robin
+}
+
+type Merge struct {
+	// RequireApprovals raises every team's merge policy minimum approvals to
+	// its required reviewer count.
+	RequireApprovals bool `yaml:"require_approvals" env-default:"false"`
 }
 
 func (db DB) DSN() string {
@@ -33,6 +49,25 @@ func (db DB) DSN() string {
 		db.Host, db.Port, db.Username, db.Password, db.DBName)
 }
 
+// validate enforces connection settings only for drivers that need them.
+func (db DB) validate() error {
+	switch db.Driver {
+	case constants.DriverPostgres:
+		if db.Host == "" || db.Port == 0 || db.Username == "" || db.Password == "" {
+			return fmt.Errorf("host, port, username and password are required for driver %q", db.Driver)
+		}
+	case constants.DriverSQLite:
+		if db.Path == "" {
+			return fmt.Errorf("path is required for driver %q", db.Driver)
+		}
+	case constants.DriverMemory:
+	default:
+		return fmt.Errorf("unknown driver %q", db.Driver)
+	}
+
+	return nil
+}
+
 func MustLoad() *Config {
 	configPath := os.Getenv("CONFIG_PATH")
 
@@ -54,5 +89,9 @@ func MustLoad() *Config {
 		log.Fatalf("Error while read config: %s", err)
 	}
 
+	if err := config.Database.validate(); err != nil {
+		log.Fatalf("Invalid database config: %s", err)
+	}
+
 	return &config
 }
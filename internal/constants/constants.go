@@ -5,3 +5,11 @@ const (
 	EnvDev   string = "dev"
 	EnvProd  string = "prod"
 )
+
+const (
+	DriverPostgres string = "postgres"
+	DriverMemory   string = "memory"
+	DriverSQLite   string = "sqlite"
+)
+
+const DefaultRequiredReviewers int = 2
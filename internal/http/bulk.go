@@ -0,0 +1,155 @@
+package http
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+)
+
+// bulkStorage is a view of a storage for reassigning many reviews at once.
+// Everything a selector reads is queried once per team and then kept up to
+// date in memory, and replacements are queued, so the number of statements
+// does not grow with the number of pull requests. flush writes the queued
+// changes back.
+type bulkStorage struct {
+	*storage.Storage
+
+	activeUsers  *activeUsersCache
+	openReviews  *openReviewCountsCache
+	rotations    *rotationCache
+	replacements []models.ReviewerReplacement
+}
+
+func newBulkStorage(st *storage.Storage) *bulkStorage {
+	b := &bulkStorage{
+		activeUsers: &activeUsersCache{UserStorage: st.UserStorage, teams: map[string][]models.User{}},
+		openReviews: &openReviewCountsCache{PullRequestStorage: st.PullRequestStorage, teams: map[string]map[string]int{}},
+		rotations:   &rotationCache{TeamStorage: st.TeamStorage, positions: map[string]int64{}, pending: map[string]int{}},
+	}
+
+	view := *st
+	view.UserStorage = b.activeUsers
+	view.PullRequestStorage = b.openReviews
+	view.TeamStorage = b.rotations
+	b.Storage = &view
+
+	return b
+}
+
+// replace queues swapping oldID, a member of oldTeam, for picked on the pull
+// request and counts the review towards picked's load.
+func (b *bulkStorage) replace(prID, oldID, oldTeam string, picked pickedReviewer) {
+	b.replacements = append(b.replacements, models.ReviewerReplacement{
+		PullRequestID: prID,
+		OldReviewerID: oldID,
+		NewReviewerID: picked.User.UserID,
+		Seed:          picked.Seed,
+	})
+
+	if counts, ok := b.openReviews.teams[oldTeam]; ok {
+		counts[oldID]--
+	}
+	if counts, ok := b.openReviews.teams[picked.User.TeamName]; ok {
+		counts[picked.User.UserID]++
+	}
+}
+
+func (b *bulkStorage) flush(ctx context.Context) error {
+	if err := b.rotations.flush(ctx); err != nil {
+		return err
+	}
+
+	if len(b.replacements) == 0 {
+		return nil
+	}
+
+	return b.openReviews.PullRequestStorage.ReplaceReviewers(ctx, b.replacements)
+}
+
+// activeUsersCache memoizes GetActiveUsersByTeam. Bulk operations do not
+// change who is active once they start picking reviewers.
+type activeUsersCache struct {
+	storage.UserStorage
+	teams map[string][]models.User
+}
+
+func (c *activeUsersCache) GetActiveUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
+	if users, ok := c.teams[teamName]; ok {
+		return users, nil
+	}
+
+	users, err := c.UserStorage.GetActiveUsersByTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	c.teams[teamName] = users
+
+	return users, nil
+}
+
+// openReviewCountsCache memoizes GetOpenReviewCountsByTeam; bulkStorage.replace
+// keeps the counts current.
+type openReviewCountsCache struct {
+	storage.PullRequestStorage
+	teams map[string]map[string]int
+}
+
+func (c *openReviewCountsCache) GetOpenReviewCountsByTeam(ctx context.Context, teamName string) (map[string]int, error) {
+	if counts, ok := c.teams[teamName]; ok {
+		return counts, nil
+	}
+
+	counts, err := c.PullRequestStorage.GetOpenReviewCountsByTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	c.teams[teamName] = counts
+
+	return counts, nil
+}
+
+// rotationCache advances round-robin cursors in memory. The first read of a
+// team goes through storage, which holds the rotation row for the rest of the
+// transaction, so concurrent requests still get distinct positions.
+type rotationCache struct {
+	storage.TeamStorage
+	positions map[string]int64
+	pending   map[string]int
+}
+
+func (c *rotationCache) AdvanceRotation(ctx context.Context, teamName string, step int) (int64, error) {
+	position, ok := c.positions[teamName]
+	if !ok {
+		var err error
+		position, err = c.TeamStorage.AdvanceRotation(ctx, teamName, 0)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	c.positions[teamName] = position + int64(step)
+	c.pending[teamName] += step
+
+	return position, nil
+}
+
+func (c *rotationCache) flush(ctx context.Context) error {
+	teams := make([]string, 0, len(c.pending))
+	for teamName, step := range c.pending {
+		if step != 0 {
+			teams = append(teams, teamName)
+		}
+	}
+	sort.Strings(teams)
+
+	for _, teamName := range teams {
+		if _, err := c.TeamStorage.AdvanceRotation(ctx, teamName, c.pending[teamName]); err != nil {
+			return err
+		}
+		delete(c.pending, teamName)
+	}
+
+	return nil
+}
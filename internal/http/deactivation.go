@@ -0,0 +1,301 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
+)
+
+const (
+	reassignStatusReassigned  = "REASSIGNED"
+	reassignStatusNoCandidate = "NO_CANDIDATE"
+)
+
+// reassignment reports what happened to one open review of a deactivated
+// user.
+type reassignment struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+	Status        string `json:"status"`
+	ReplacedBy    string `json:"replaced_by,omitempty"`
+	Fallback      bool   `json:"fallback,omitempty"`
+	Message       string `json:"message,omitempty"`
+}
+
+func (h *Handler) DeactivateAndReassign(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID string `json:"user_id"`
+		Reason string `json:"reason"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+
+	if req.UserID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+
+	ctx := r.Context()
+
+	var (
+		updated       models.User
+		reassignments []reassignment
+	)
+
+	err := h.Storage.WithTx(ctx, func(st *storage.Storage) error {
+		user, err := st.UserStorage.GetUserByID(ctx, req.UserID)
+		if errors.Is(err, storageErrors.ErrUserNotFound) {
+			return newAPIError(http.StatusNotFound, "NOT_FOUND", "user not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		if user.IsActive {
+			if err := st.UserStorage.SetUserActiveStatus(ctx, req.UserID, false); err != nil {
+				return err
+			}
+
+			if err := recordEvent(ctx, st, r, models.AssignmentEvent{
+				EventType: models.EventDeactivate,
+				UserID:    req.UserID,
+				Reason:    req.Reason,
+			}); err != nil {
+				return err
+			}
+		}
+
+		updated, err = st.UserStorage.GetUserByID(ctx, req.UserID)
+		if err != nil {
+			return err
+		}
+
+		reassignments, err = h.reassignOpenReviews(ctx, st, r, updated, req.Reason)
+		return err
+	})
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"user":          updated,
+		"reassignments": reassignments,
+	})
+}
+
+// reassignOpenReviews moves every OPEN review of user to a replacement using
+// the same rules as ReassignReviewer. A pull request without a candidate is
+// reported and keeps user as reviewer; it does not abort the others.
+func (h *Handler) reassignOpenReviews(
+	ctx context.Context,
+	st *storage.Storage,
+	r *http.Request,
+	user models.User,
+	reason string,
+) ([]reassignment, error) {
+	prs, err := st.PullRequestStorage.GetPullRequestsByReviewer(ctx, user.UserID, models.PullRequestQuery{
+		PullRequestFilter: models.PullRequestFilter{Statuses: []models.PullRequestStatus{models.StatusOpen}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := []reassignment{}
+
+	for _, short := range prs {
+		if err := st.PullRequestStorage.LockPullRequest(ctx, short.PullRequestID); err != nil {
+			return nil, err
+		}
+
+		pr, err := st.PullRequestStorage.GetPullRequestByID(ctx, short.PullRequestID)
+		if err != nil {
+			return nil, err
+		}
+
+		replacement, err := h.replaceReviewer(ctx, st, r, pr, user, reason)
+
+		var apiErr *apiError
+		switch {
+		case err == nil:
+			result = append(result, reassignment{
+				PullRequestID: pr.PullRequestID,
+				ReviewerID:    user.UserID,
+				Status:        reassignStatusReassigned,
+				ReplacedBy:    replacement.User.UserID,
+				Fallback:      replacement.Fallback,
+			})
+		case errors.As(err, &apiErr) && apiErr.code == reassignStatusNoCandidate:
+			result = append(result, reassignment{
+				PullRequestID: pr.PullRequestID,
+				ReviewerID:    user.UserID,
+				Status:        reassignStatusNoCandidate,
+				Message:       apiErr.message,
+			})
+		default:
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (h *Handler) DeactivateTeam(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName string `json:"team_name"`
+		Reason   string `json:"reason"`
+		DryRun   bool   `json:"dry_run"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+
+	if req.TeamName == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+
+	ctx := r.Context()
+
+	var (
+		deactivated   []string
+		reassignments []reassignment
+	)
+
+	err := h.runTx(ctx, req.DryRun, func(st *storage.Storage) error {
+		team, err := st.TeamStorage.GetTeamByName(ctx, req.TeamName)
+		if errors.Is(err, storageErrors.ErrTeamNotFound) {
+			return newAPIError(http.StatusNotFound, "NOT_FOUND", "team not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		deactivated, err = st.UserStorage.SetTeamActiveStatus(ctx, req.TeamName, false)
+		if err != nil {
+			return err
+		}
+
+		events := make([]models.AssignmentEvent, 0, len(deactivated))
+		for _, id := range deactivated {
+			events = append(events, models.AssignmentEvent{
+				EventType: models.EventDeactivate,
+				UserID:    id,
+				Reason:    req.Reason,
+			})
+		}
+
+		if err := recordEvents(ctx, st, r, events); err != nil {
+			return err
+		}
+
+		reassignments, err = h.reassignTeamReviews(ctx, st, r, team, req.Reason)
+		return err
+	})
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"team_name":         req.TeamName,
+		"dry_run":           req.DryRun,
+		"deactivated_users": deactivated,
+		"reassignments":     reassignments,
+	})
+}
+
+// reassignTeamReviews replaces every reviewer from team on OPEN pull requests
+// once the whole team is inactive, so replacements come from its fallback
+// teams. Affected reviews are loaded and locked with one query, and selection
+// runs against a bulkStorage, so the replacements and their events are
+// written with a fixed number of statements however many reviews move.
+func (h *Handler) reassignTeamReviews(
+	ctx context.Context,
+	st *storage.Storage,
+	r *http.Request,
+	team models.Team,
+	reason string,
+) ([]reassignment, error) {
+	assignments, err := st.PullRequestStorage.GetOpenReviewsByTeam(ctx, team.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	bulk := newBulkStorage(st)
+	result := []reassignment{}
+	events := []models.AssignmentEvent{}
+
+	for start := 0; start < len(assignments); {
+		prID := assignments[start].PullRequestID
+		authorID := assignments[start].AuthorID
+
+		end := start
+		assigned := map[string]struct{}{}
+		var replace []string
+		for ; end < len(assignments) && assignments[end].PullRequestID == prID; end++ {
+			a := assignments[end]
+			assigned[a.ReviewerID] = struct{}{}
+			if a.ReviewerTeam == team.TeamName {
+				replace = append(replace, a.ReviewerID)
+			}
+		}
+		start = end
+
+		picked, err := h.pickReviewers(ctx, bulk.Storage, team, prID, authorID, assigned, len(replace))
+		if err != nil {
+			return nil, err
+		}
+
+		for i, oldID := range replace {
+			if i >= len(picked) {
+				result = append(result, reassignment{
+					PullRequestID: prID,
+					ReviewerID:    oldID,
+					Status:        reassignStatusNoCandidate,
+					Message:       "no active replacement candidate in fallback teams",
+				})
+				continue
+			}
+
+			bulk.replace(prID, oldID, team.TeamName, picked[i])
+			events = append(events, models.AssignmentEvent{
+				EventType:     models.EventReassign,
+				PullRequestID: prID,
+				UserID:        oldID,
+				ReplacementID: picked[i].User.UserID,
+				Reason:        reason,
+			})
+
+			result = append(result, reassignment{
+				PullRequestID: prID,
+				ReviewerID:    oldID,
+				Status:        reassignStatusReassigned,
+				ReplacedBy:    picked[i].User.UserID,
+				Fallback:      picked[i].Fallback,
+			})
+		}
+	}
+
+	if err := bulk.flush(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := recordEvents(ctx, st, r, events); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,205 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/assignment"
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage/memory"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage/sqlite"
+)
+
+// callCounter counts storage calls that each cost a database round trip.
+type callCounter map[string]int
+
+type countingUsers struct {
+	storage.UserStorage
+	calls callCounter
+}
+
+func (u countingUsers) GetActiveUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
+	u.calls["GetActiveUsersByTeam"]++
+	return u.UserStorage.GetActiveUsersByTeam(ctx, teamName)
+}
+
+type countingTeams struct {
+	storage.TeamStorage
+	calls callCounter
+}
+
+func (ts countingTeams) AdvanceRotation(ctx context.Context, teamName string, step int) (int64, error) {
+	ts.calls["AdvanceRotation"]++
+	return ts.TeamStorage.AdvanceRotation(ctx, teamName, step)
+}
+
+type countingPullRequests struct {
+	storage.PullRequestStorage
+	calls callCounter
+}
+
+func (prs countingPullRequests) GetOpenReviewCountsByTeam(ctx context.Context, teamName string) (map[string]int, error) {
+	prs.calls["GetOpenReviewCountsByTeam"]++
+	return prs.PullRequestStorage.GetOpenReviewCountsByTeam(ctx, teamName)
+}
+
+func (prs countingPullRequests) AddReviewer(ctx context.Context, prID, userID string, seed *int64) error {
+	prs.calls["AddReviewer"]++
+	return prs.PullRequestStorage.AddReviewer(ctx, prID, userID, seed)
+}
+
+func (prs countingPullRequests) RemoveReviewer(ctx context.Context, prID, userID string) error {
+	prs.calls["RemoveReviewer"]++
+	return prs.PullRequestStorage.RemoveReviewer(ctx, prID, userID)
+}
+
+func (prs countingPullRequests) ReplaceReviewers(ctx context.Context, replacements []models.ReviewerReplacement) error {
+	prs.calls["ReplaceReviewers"]++
+	return prs.PullRequestStorage.ReplaceReviewers(ctx, replacements)
+}
+
+type countingEvents struct {
+	storage.EventStorage
+	calls callCounter
+}
+
+func (es countingEvents) AddEvent(ctx context.Context, event models.AssignmentEvent) error {
+	es.calls["AddEvent"]++
+	return es.EventStorage.AddEvent(ctx, event)
+}
+
+func (es countingEvents) AddEvents(ctx context.Context, events []models.AssignmentEvent) error {
+	es.calls["AddEvents"]++
+	return es.EventStorage.AddEvents(ctx, events)
+}
+
+// seedTeamReviews creates a "backend" team of members users falling back to
+// an eight member "platform" team, and prs pull requests by platform members,
+// each reviewed by two backend members.
+func seedTeamReviews(t testing.TB, st *storage.Storage, srv http.Handler, members, prs int) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	platform := make([]member, 0, 8)
+	for i := range 8 {
+		platform = append(platform, member{UserID: fmt.Sprintf("p%d", i), Username: "p", IsActive: true})
+	}
+
+	backend := make([]member, 0, members)
+	for i := range members {
+		backend = append(backend, member{UserID: fmt.Sprintf("b%d", i), Username: "b", IsActive: true})
+	}
+
+	for _, team := range []map[string]any{
+		{"team_name": "platform", "members": platform},
+		{"team_name": "backend", "fallback_teams": []string{"platform"}, "members": backend},
+	} {
+		mustStatus(t, do(t, srv, http.MethodPost, "/team/add", team), http.StatusCreated)
+	}
+
+	for i := range prs {
+		prID := fmt.Sprintf("pr-%d", i)
+		if err := st.PullRequestStorage.CreatePullRequest(ctx, prID, prID, platform[i%len(platform)].UserID, models.StatusOpen); err != nil {
+			t.Fatalf("CreatePullRequest: %v", err)
+		}
+
+		for _, j := range []int{i, i + 1} {
+			if err := st.PullRequestStorage.AddReviewer(ctx, prID, backend[j%members].UserID, nil); err != nil {
+				t.Fatalf("AddReviewer: %v", err)
+			}
+		}
+	}
+}
+
+// TestDeactivateTeamStatements checks that deactivating a team issues the
+// same storage calls whether it has a few or many open reviews.
+func TestDeactivateTeamStatements(t *testing.T) {
+	selectors := map[string]assignment.ReviewerSelector{
+		assignment.StrategyFirst:        &assignment.FirstSelector{},
+		assignment.StrategyLoadBalanced: &assignment.LoadBalancedSelector{},
+		assignment.StrategyRandom:       assignment.NewRandomSelector(nil),
+		assignment.StrategyRoundRobin:   &assignment.RoundRobinSelector{},
+	}
+
+	for name, selector := range selectors {
+		t.Run(name, func(t *testing.T) {
+			var counts []callCounter
+
+			for _, prs := range []int{5, 50} {
+				st := memory.NewMemoryStorage()
+				srv := newTestServer(st, selector)
+				seedTeamReviews(t, st, srv, 20, prs)
+
+				calls := callCounter{}
+				wrapTx(st, func(st *storage.Storage) {
+					st.UserStorage = countingUsers{st.UserStorage, calls}
+					st.TeamStorage = countingTeams{st.TeamStorage, calls}
+					st.PullRequestStorage = countingPullRequests{st.PullRequestStorage, calls}
+					st.EventStorage = countingEvents{st.EventStorage, calls}
+				})
+
+				rec := do(t, srv, http.MethodPost, "/team/deactivate", map[string]any{"team_name": "backend"})
+				mustStatus(t, rec, http.StatusOK)
+
+				resp := decode[struct {
+					DeactivatedUsers []string       `json:"deactivated_users"`
+					Reassignments    []reassignment `json:"reassignments"`
+				}](t, rec)
+
+				if len(resp.DeactivatedUsers) != 20 || len(resp.Reassignments) != 2*prs {
+					t.Fatalf("deactivated %d users and %d reviews, want 20 and %d",
+						len(resp.DeactivatedUsers), len(resp.Reassignments), 2*prs)
+				}
+				for _, r := range resp.Reassignments {
+					if r.Status != reassignStatusReassigned {
+						t.Fatalf("reassignment %+v, want %s", r, reassignStatusReassigned)
+					}
+				}
+
+				if calls["AddEvent"] != 0 || calls["AddReviewer"] != 0 || calls["RemoveReviewer"] != 0 {
+					t.Errorf("per-row writes: %v", calls)
+				}
+
+				counts = append(counts, calls)
+			}
+
+			if !reflect.DeepEqual(counts[0], counts[1]) {
+				t.Errorf("calls grow with open reviews: %v for 5, %v for 50", counts[0], counts[1])
+			}
+		})
+	}
+}
+
+func BenchmarkDeactivateTeam(b *testing.B) {
+	for _, selector := range []string{assignment.StrategyLoadBalanced, assignment.StrategyRoundRobin} {
+		b.Run(selector, func(b *testing.B) {
+			sel, err := assignment.New(selector)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				st, err := sqlite.NewSQLiteStorage(filepath.Join(b.TempDir(), "bench.db"))
+				if err != nil {
+					b.Fatalf("NewSQLiteStorage: %v", err)
+				}
+				srv := newTestServer(st, sel)
+				seedTeamReviews(b, st, srv, 300, 600)
+				b.StartTimer()
+
+				rec := do(b, srv, http.MethodPost, "/team/deactivate", map[string]any{"team_name": "backend"})
+				mustStatus(b, rec, http.StatusOK)
+
+				b.StopTimer()
+				st.Close()
+			}
+		})
+	}
+}
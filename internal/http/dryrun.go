@@ -0,0 +1,33 @@
+package http
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+)
+
+// errDryRun rolls back a transaction whose outcome is only previewed.
+var errDryRun = errors.New("dry run")
+
+// runTx runs fn in a transaction. With dryRun set the transaction is rolled
+// back after fn succeeds, so the caller can report what would have happened
+// without persisting it. Selector state such as round-robin rotations is
+// rolled back too, so a preview matches the next real request; only the
+// random strategy draws a new seed each time.
+func (h *Handler) runTx(ctx context.Context, dryRun bool, fn func(st *storage.Storage) error) error {
+	err := h.Storage.WithTx(ctx, func(st *storage.Storage) error {
+		if err := fn(st); err != nil {
+			return err
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,189 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/assignment"
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
+)
+
+// stateSnapshot is everything a dry run must leave untouched.
+type stateSnapshot struct {
+	PullRequests map[string]models.PullRequest
+	PREvents     map[string][]models.AssignmentEvent
+	UserEvents   map[string][]models.AssignmentEvent
+	Users        []models.User
+	Rotation     int64
+}
+
+func snapshot(t *testing.T, st *storage.Storage, prIDs, userIDs []string) stateSnapshot {
+	t.Helper()
+
+	ctx := context.Background()
+
+	s := stateSnapshot{
+		PullRequests: map[string]models.PullRequest{},
+		PREvents:     map[string][]models.AssignmentEvent{},
+		UserEvents:   map[string][]models.AssignmentEvent{},
+	}
+
+	for _, id := range prIDs {
+		pr, err := st.PullRequestStorage.GetPullRequestByID(ctx, id)
+		if errors.Is(err, storageErrors.ErrPRNotFound) {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("GetPullRequestByID(%q): %v", id, err)
+		}
+		s.PullRequests[id] = pr
+
+		if s.PREvents[id], err = st.EventStorage.GetEventsByPR(ctx, id); err != nil {
+			t.Fatalf("GetEventsByPR(%q): %v", id, err)
+		}
+	}
+
+	for _, id := range userIDs {
+		events, err := st.EventStorage.GetEventsByUser(ctx, id)
+		if err != nil {
+			t.Fatalf("GetEventsByUser(%q): %v", id, err)
+		}
+		s.UserEvents[id] = events
+	}
+
+	var err error
+	if s.Users, err = st.TeamStorage.GetUsersByTeam(ctx, "backend"); err != nil {
+		t.Fatalf("GetUsersByTeam: %v", err)
+	}
+
+	// A zero step reads the round-robin cursor without moving it.
+	if s.Rotation, err = st.TeamStorage.AdvanceRotation(ctx, "backend", 0); err != nil {
+		t.Fatalf("AdvanceRotation: %v", err)
+	}
+
+	return s
+}
+
+// testDryRun sets up team backend with one open pull request pr-1 authored by
+// u1 and checks that the dry-run request returns the same body as the real
+// request that follows it, and changes nothing in between.
+func testDryRun(t *testing.T, path string, body map[string]any, wantStatus int, check func(t *testing.T, resp map[string]any)) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			st := b.newStorage(t)
+			srv := newTestServer(st, &assignment.RoundRobinSelector{})
+
+			userIDs := []string{"u1", "u2", "u3", "u4", "u5"}
+			prIDs := []string{"pr-1", "pr-2"}
+
+			mustAddTeam(t, srv, "backend", 2, userIDs...)
+
+			rec := do(t, srv, http.MethodPost, "/pullRequest/create", map[string]any{
+				"pull_request_id":   "pr-1",
+				"pull_request_name": "Add search",
+				"author_id":         "u1",
+			})
+			mustStatus(t, rec, http.StatusCreated)
+
+			before := snapshot(t, st, prIDs, userIDs)
+
+			preview := map[string]any{"dry_run": true}
+			for k, v := range body {
+				preview[k] = v
+			}
+
+			rec = do(t, srv, http.MethodPost, path, preview)
+			mustStatus(t, rec, http.StatusOK)
+			previewed := decode[map[string]any](t, rec)
+
+			if previewed["dry_run"] != true {
+				t.Errorf("dry_run = %v, want true", previewed["dry_run"])
+			}
+			check(t, previewed)
+
+			if after := snapshot(t, st, prIDs, userIDs); !reflect.DeepEqual(after, before) {
+				t.Fatalf("dry run changed storage:\nbefore %+v\nafter  %+v", before, after)
+			}
+
+			rec = do(t, srv, http.MethodPost, path, body)
+			mustStatus(t, rec, wantStatus)
+			applied := decode[map[string]any](t, rec)
+
+			delete(previewed, "dry_run")
+			delete(applied, "dry_run")
+
+			if !reflect.DeepEqual(previewed, applied) {
+				t.Errorf("preview %v, real request %v", previewed, applied)
+			}
+		})
+	}
+}
+
+func reviewerIDs(t *testing.T, resp map[string]any) []any {
+	t.Helper()
+
+	pr, ok := resp["pr"].(map[string]any)
+	if !ok {
+		t.Fatalf("response has no pr: %v", resp)
+	}
+
+	reviewers, _ := pr["assigned_reviewers"].([]any)
+
+	return reviewers
+}
+
+func TestCreatePullRequestDryRun(t *testing.T) {
+	testDryRun(t, "/pullRequest/create", map[string]any{
+		"pull_request_id":   "pr-2",
+		"pull_request_name": "Add filters",
+		"author_id":         "u1",
+	}, http.StatusCreated, func(t *testing.T, resp map[string]any) {
+		if reviewers := reviewerIDs(t, resp); len(reviewers) != 2 {
+			t.Errorf("previewed reviewers = %v, want 2 of them", reviewers)
+		}
+	})
+}
+
+func TestReassignReviewerDryRun(t *testing.T) {
+	testDryRun(t, "/pullRequest/reassign", map[string]any{
+		"pull_request_id": "pr-1",
+		"old_reviewer_id": "u2",
+	}, http.StatusOK, func(t *testing.T, resp map[string]any) {
+		replacedBy, _ := resp["replaced_by"].(string)
+		if replacedBy == "" || replacedBy == "u2" || replacedBy == "u1" {
+			t.Errorf("replaced_by = %q, want another team member", replacedBy)
+		}
+
+		for _, id := range reviewerIDs(t, resp) {
+			if id == "u2" {
+				t.Errorf("previewed reviewers %v still include u2", reviewerIDs(t, resp))
+			}
+		}
+	})
+}
+
+func TestSetUserActiveDryRun(t *testing.T) {
+	testDryRun(t, "/users/setIsActive", map[string]any{
+		"user_id":               "u2",
+		"is_active":             false,
+		"reassign_open_reviews": true,
+	}, http.StatusOK, func(t *testing.T, resp map[string]any) {
+		user, _ := resp["user"].(map[string]any)
+		if user["is_active"] != false {
+			t.Errorf("previewed user = %v, want inactive", user)
+		}
+
+		reassignments, _ := resp["reassignments"].([]any)
+		if len(reassignments) != 1 {
+			t.Fatalf("previewed reassignments = %v, want one", reassignments)
+		}
+		if r, _ := reassignments[0].(map[string]any); r["pull_request_id"] != "pr-1" || r["replaced_by"] == nil {
+			t.Errorf("previewed reassignment = %v, want pr-1 replaced", r)
+		}
+	})
+}
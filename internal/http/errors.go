@@ -0,0 +1,55 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// apiError is returned from inside storage transactions to abort them with a
+// specific HTTP response instead of a generic 500.
+type apiError struct {
+	status  int
+	code    string
+	message string
+	details map[string]any
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func newAPIError(status int, code, message string) *apiError {
+	return &apiError{status: status, code: code, message: message}
+}
+
+func writeErr(w http.ResponseWriter, err error) {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		if apiErr.details != nil {
+			writeErrorDetails(w, apiErr.status, apiErr.code, apiErr.message, apiErr.details)
+			return
+		}
+		writeError(w, apiErr.status, apiErr.code, apiErr.message)
+		return
+	}
+
+	writeError(w, 500, "UNKNOWN", err.Error())
+}
+
+// writeErrorDetails is writeError with extra machine-readable fields merged
+// into the error object.
+func writeErrorDetails(w http.ResponseWriter, status int, code, msg string, details map[string]any) {
+	body := map[string]any{
+		"code":    code,
+		"message": msg,
+	}
+	for k, v := range details {
+		body[k] = v
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(map[string]any{"error": body})
+}
@@ -7,33 +7,57 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/pacahar/pr-reviewer-assignment/internal/assignment"
+	"github.com/pacahar/pr-reviewer-assignment/internal/constants"
 	"github.com/pacahar/pr-reviewer-assignment/internal/models"
 	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
 	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
 )
 
 type Handler struct {
-	Storage *storage.Storage
-	Log     *slog.Logger
+	Storage          *storage.Storage
+	Selector         assignment.ReviewerSelector
+	RequireApprovals bool
+	Log              *slog.Logger
 }
 
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /team/add", h.CreateTeam)
 	mux.HandleFunc("GET /team/get", h.GetTeam)
+	mux.HandleFunc("POST /team/update", h.UpdateTeam)
+	mux.HandleFunc("POST /team/deactivate", h.DeactivateTeam)
+	mux.HandleFunc("GET /team/list", h.ListTeams)
+	mux.HandleFunc("POST /team/addMember", h.AddTeamMember)
+	mux.HandleFunc("POST /team/removeMember", h.RemoveTeamMember)
+	mux.HandleFunc("POST /team/rename", h.RenameTeam)
+	mux.HandleFunc("DELETE /team", h.DeleteTeam)
 
 	mux.HandleFunc("POST /users/setIsActive", h.SetUserActive)
+	mux.HandleFunc("POST /users/deactivateAndReassign", h.DeactivateAndReassign)
 	mux.HandleFunc("GET /users/getReview", h.GetUserReviews)
+	mux.HandleFunc("GET /users/history", h.GetUserHistory)
 
 	mux.HandleFunc("POST /pullRequest/create", h.CreatePullRequest)
 	mux.HandleFunc("POST /pullRequest/merge", h.MergePullRequest)
 	mux.HandleFunc("POST /pullRequest/reassign", h.ReassignReviewer)
+	mux.HandleFunc("POST /pullRequest/close", h.ClosePullRequest)
+	mux.HandleFunc("POST /pullRequest/reopen", h.ReopenPullRequest)
+	mux.HandleFunc("POST /pullRequest/markReady", h.MarkPullRequestReady)
+	mux.HandleFunc("POST /pullRequest/review", h.SubmitReview)
+	mux.HandleFunc("GET /pullRequest/history", h.GetPullRequestHistory)
+	mux.HandleFunc("GET /pullRequest/list", h.ListPullRequests)
+	mux.HandleFunc("GET /stats", h.GetStats)
+	mux.HandleFunc("GET /stats/fairness", h.GetFairness)
 
 }
 
 func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		TeamName string `json:"team_name"`
-		Members  []struct {
+		TeamName          string              `json:"team_name"`
+		RequiredReviewers *int                `json:"required_reviewers"`
+		FallbackTeams     []string            `json:"fallback_teams"`
+		MergePolicy       *models.MergePolicy `json:"merge_policy"`
+		Members           []struct {
 			UserID   string `json:"user_id"`
 			Username string `json:"username"`
 			IsActive bool   `json:"is_active"`
@@ -50,59 +74,116 @@ func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err := h.Storage.TeamStorage.GetTeamByName(r.Context(), req.TeamName)
-	if err == nil {
-		writeError(w, http.StatusBadRequest, "TEAM_EXISTS", "team_name already exists")
-		return
+	requiredReviewers := constants.DefaultRequiredReviewers
+	if req.RequiredReviewers != nil {
+		requiredReviewers = *req.RequiredReviewers
 	}
-	if err != nil && !errors.Is(err, storageErrors.ErrTeamNotFound) {
-		writeError(w, 500, "UNKNOWN", err.Error())
+
+	if requiredReviewers < 0 {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "required_reviewers must not be negative")
 		return
 	}
 
-	if err := h.Storage.TeamStorage.CreateTeam(r.Context(), req.TeamName); err != nil {
-		writeError(w, 500, "UNKNOWN", err.Error())
+	if req.FallbackTeams == nil {
+		req.FallbackTeams = []string{}
+	}
+
+	if req.MergePolicy == nil {
+		req.MergePolicy = &models.MergePolicy{}
+	}
+
+	if req.MergePolicy.MinApprovals < 0 {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "min_approvals must not be negative")
 		return
 	}
 
-	for _, m := range req.Members {
+	ctx := r.Context()
 
-		_, err := h.Storage.UserStorage.GetUserByID(r.Context(), m.UserID)
+	err := h.Storage.WithTx(ctx, func(st *storage.Storage) error {
+		_, err := st.TeamStorage.GetTeamByName(ctx, req.TeamName)
+		if err == nil {
+			return newAPIError(http.StatusBadRequest, "TEAM_EXISTS", "team_name already exists")
+		}
+		if !errors.Is(err, storageErrors.ErrTeamNotFound) {
+			return err
+		}
 
-		switch {
-		case errors.Is(err, storageErrors.ErrUserNotFound):
-			if err := h.Storage.UserStorage.CreateUser(
-				r.Context(), m.UserID, m.Username, req.TeamName,
-			); err != nil {
-				writeError(w, 500, "UNKNOWN", err.Error())
-				return
+		if err := checkFallbackTeams(ctx, st, req.TeamName, req.FallbackTeams); err != nil {
+			return err
+		}
+
+		if err := st.TeamStorage.CreateTeam(ctx, req.TeamName, requiredReviewers); err != nil {
+			return err
+		}
+
+		if err := st.TeamStorage.SetFallbackTeams(ctx, req.TeamName, req.FallbackTeams); err != nil {
+			return err
+		}
+
+		if err := st.TeamStorage.SetMergePolicy(ctx, req.TeamName, *req.MergePolicy); err != nil {
+			return err
+		}
+
+		for _, m := range req.Members {
+
+			user, err := st.UserStorage.GetUserByID(ctx, m.UserID)
+
+			switch {
+			case errors.Is(err, storageErrors.ErrUserNotFound):
+				if err := st.UserStorage.CreateUser(
+					ctx, m.UserID, m.Username, req.TeamName,
+				); err != nil {
+					return err
+				}
+				user.IsActive = true
+
+			case err == nil:
+				if err := st.UserStorage.SetUserTeam(
+					ctx, m.UserID, req.TeamName,
+				); err != nil {
+					return err
+				}
+
+			default:
+				return err
 			}
 
-		case err == nil:
-			if err := h.Storage.UserStorage.SetUserTeam(
-				r.Context(), m.UserID, req.TeamName,
+			if err := st.UserStorage.SetUserActiveStatus(
+				ctx, m.UserID, m.IsActive,
 			); err != nil {
-				writeError(w, 500, "UNKNOWN", err.Error())
-				return
+				return err
 			}
 
-		default:
-			writeError(w, 500, "UNKNOWN", err.Error())
-			return
+			if user.IsActive != m.IsActive {
+				eventType := models.EventDeactivate
+				if m.IsActive {
+					eventType = models.EventActivate
+				}
+
+				if err := recordEvent(ctx, st, r, models.AssignmentEvent{
+					EventType: eventType,
+					UserID:    m.UserID,
+					Reason:    "team " + req.TeamName + " created",
+				}); err != nil {
+					return err
+				}
+			}
 		}
 
-		if err := h.Storage.UserStorage.SetUserActiveStatus(
-			r.Context(), m.UserID, m.IsActive,
-		); err != nil {
-			writeError(w, 500, "UNKNOWN", err.Error())
-			return
-		}
+		return nil
+	})
+	if err != nil {
+		writeErr(w, err)
+		return
 	}
 
 	resp := map[string]any{
 		"team": map[string]any{
-			"team_name": req.TeamName,
-			"members":   req.Members,
+			"team_name":          req.TeamName,
+			"required_reviewers": requiredReviewers,
+			"fallback_teams":     req.FallbackTeams,
+			"merge_policy":       req.MergePolicy,
+			"members":            req.Members,
 		},
 	}
 
@@ -136,8 +217,11 @@ func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := map[string]any{
-		"team_name": team.TeamName,
-		"members":   []any{},
+		"team_name":          team.TeamName,
+		"required_reviewers": team.RequiredReviewers,
+		"fallback_teams":     team.FallbackTeams,
+		"merge_policy":       team.MergePolicy,
+		"members":            []any{},
 	}
 
 	members := make([]any, 0, len(users))
@@ -156,10 +240,12 @@ func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
-func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) UpdateTeam(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		UserID   string `json:"user_id"`
-		IsActive bool   `json:"is_active"`
+		TeamName          string              `json:"team_name"`
+		RequiredReviewers *int                `json:"required_reviewers"`
+		FallbackTeams     *[]string           `json:"fallback_teams"`
+		MergePolicy       *models.MergePolicy `json:"merge_policy"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -167,36 +253,156 @@ func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.UserID == "" {
-		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+	if req.TeamName == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
 		return
 	}
 
-	ctx := r.Context()
+	if req.RequiredReviewers == nil && req.FallbackTeams == nil && req.MergePolicy == nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "nothing to update")
+		return
+	}
 
-	_, err := h.Storage.UserStorage.GetUserByID(ctx, req.UserID)
-	if errors.Is(err, storageErrors.ErrUserNotFound) {
-		writeError(w, http.StatusNotFound, "NOT_FOUND", "user not found")
+	if req.RequiredReviewers != nil && *req.RequiredReviewers < 0 {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "required_reviewers must not be negative")
+		return
+	}
+
+	if req.MergePolicy != nil && req.MergePolicy.MinApprovals < 0 {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "min_approvals must not be negative")
 		return
 	}
+
+	ctx := r.Context()
+
+	var team models.Team
+
+	err := h.Storage.WithTx(ctx, func(st *storage.Storage) error {
+		_, err := st.TeamStorage.GetTeamByName(ctx, req.TeamName)
+		if errors.Is(err, storageErrors.ErrTeamNotFound) {
+			return newAPIError(http.StatusNotFound, "NOT_FOUND", "team not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		if req.RequiredReviewers != nil {
+			if err := st.TeamStorage.SetRequiredReviewers(ctx, req.TeamName, *req.RequiredReviewers); err != nil {
+				return err
+			}
+		}
+
+		if req.FallbackTeams != nil {
+			if err := checkFallbackTeams(ctx, st, req.TeamName, *req.FallbackTeams); err != nil {
+				return err
+			}
+
+			if err := st.TeamStorage.SetFallbackTeams(ctx, req.TeamName, *req.FallbackTeams); err != nil {
+				return err
+			}
+		}
+
+		if req.MergePolicy != nil {
+			if err := st.TeamStorage.SetMergePolicy(ctx, req.TeamName, *req.MergePolicy); err != nil {
+				return err
+			}
+		}
+
+		team, err = st.TeamStorage.GetTeamByName(ctx, req.TeamName)
+		return err
+	})
 	if err != nil {
-		writeError(w, 500, "UNKNOWN", err.Error())
+		writeErr(w, err)
 		return
 	}
 
-	if err := h.Storage.UserStorage.SetUserActiveStatus(ctx, req.UserID, req.IsActive); err != nil {
-		writeError(w, 500, "UNKNOWN", err.Error())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"team": team,
+	})
+}
+
+func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID              string `json:"user_id"`
+		IsActive            bool   `json:"is_active"`
+		Reason              string `json:"reason"`
+		ReassignOpenReviews bool   `json:"reassign_open_reviews"`
+		DryRun              bool   `json:"dry_run"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+
+	if req.UserID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "user_id is required")
+		return
+	}
+
+	if req.IsActive && req.ReassignOpenReviews {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "reassign_open_reviews requires is_active=false")
 		return
 	}
 
-	updated, err := h.Storage.UserStorage.GetUserByID(ctx, req.UserID)
+	ctx := r.Context()
+
+	var (
+		updated       models.User
+		reassignments []reassignment
+	)
+
+	err := h.runTx(ctx, req.DryRun, func(st *storage.Storage) error {
+		user, err := st.UserStorage.GetUserByID(ctx, req.UserID)
+		if errors.Is(err, storageErrors.ErrUserNotFound) {
+			return newAPIError(http.StatusNotFound, "NOT_FOUND", "user not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := st.UserStorage.SetUserActiveStatus(ctx, req.UserID, req.IsActive); err != nil {
+			return err
+		}
+
+		if user.IsActive != req.IsActive {
+			eventType := models.EventDeactivate
+			if req.IsActive {
+				eventType = models.EventActivate
+			}
+
+			if err := recordEvent(ctx, st, r, models.AssignmentEvent{
+				EventType: eventType,
+				UserID:    req.UserID,
+				Reason:    req.Reason,
+			}); err != nil {
+				return err
+			}
+		}
+
+		updated, err = st.UserStorage.GetUserByID(ctx, req.UserID)
+		if err != nil {
+			return err
+		}
+
+		if req.ReassignOpenReviews {
+			reassignments, err = h.reassignOpenReviews(ctx, st, r, updated, req.Reason)
+		}
+		return err
+	})
 	if err != nil {
-		writeError(w, 500, "UNKNOWN", err.Error())
+		writeErr(w, err)
 		return
 	}
 
 	resp := map[string]any{
-		"user": updated,
+		"user":    updated,
+		"dry_run": req.DryRun,
+	}
+	if req.ReassignOpenReviews {
+		resp["reassignments"] = reassignments
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -209,6 +415,8 @@ func (h *Handler) CreatePullRequest(w http.ResponseWriter, r *http.Request) {
 		PRID   string `json:"pull_request_id"`
 		PRName string `json:"pull_request_name"`
 		Author string `json:"author_id"`
+		Draft  bool   `json:"draft"`
+		DryRun bool   `json:"dry_run"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -223,79 +431,105 @@ func (h *Handler) CreatePullRequest(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
-	_, err := h.Storage.PullRequestStorage.GetPullRequestByID(ctx, req.PRID)
-	if err == nil {
-		writeError(w, http.StatusConflict, "PR_EXISTS", "PR id already exists")
-		return
-	}
-	if err != nil && !errors.Is(err, storageErrors.ErrPRNotFound) {
-		writeError(w, 500, "UNKNOWN", err.Error())
-		return
-	}
+	var (
+		team   models.Team
+		picked []pickedReviewer
+		respPR models.PullRequest
+	)
 
-	author, err := h.Storage.UserStorage.GetUserByID(ctx, req.Author)
-	if errors.Is(err, storageErrors.ErrUserNotFound) {
-		writeError(w, http.StatusNotFound, "NOT_FOUND", "author not found")
-		return
-	}
-	if err != nil {
-		writeError(w, 500, "UNKNOWN", err.Error())
-		return
-	}
+	err := h.runTx(ctx, req.DryRun, func(st *storage.Storage) error {
+		_, err := st.PullRequestStorage.GetPullRequestByID(ctx, req.PRID)
+		if err == nil {
+			return newAPIError(http.StatusConflict, "PR_EXISTS", "PR id already exists")
+		}
+		if !errors.Is(err, storageErrors.ErrPRNotFound) {
+			return err
+		}
 
-	teammates, err := h.Storage.UserStorage.GetActiveUsersByTeam(ctx, author.TeamName)
-	if err != nil {
-		writeError(w, 500, "UNKNOWN", err.Error())
-		return
-	}
+		author, err := st.UserStorage.GetUserByID(ctx, req.Author)
+		if errors.Is(err, storageErrors.ErrUserNotFound) {
+			return newAPIError(http.StatusNotFound, "NOT_FOUND", "author not found")
+		}
+		if err != nil {
+			return err
+		}
 
-	reviewers := make([]models.User, 0)
-	for _, u := range teammates {
-		if u.UserID != author.UserID {
-			reviewers = append(reviewers, u)
+		team, err = st.TeamStorage.GetTeamByName(ctx, author.TeamName)
+		if err != nil {
+			return err
 		}
-	}
 
-	assigned := []string{}
-	if len(reviewers) > 0 {
-		assigned = append(assigned, reviewers[0].UserID)
-	}
-	if len(reviewers) > 1 {
-		assigned = append(assigned, reviewers[1].UserID)
-	}
+		status := models.StatusOpen
+		if req.Draft {
+			// Reviewers are assigned once the draft is marked ready.
+			status = models.StatusDraft
+		} else {
+			picked, err = h.pickReviewers(ctx, st, team, req.PRID, author.UserID, nil, team.RequiredReviewers)
+			if err != nil {
+				return err
+			}
+		}
 
-	if err := h.Storage.PullRequestStorage.CreatePullRequest(ctx, req.PRID, req.PRName, req.Author); err != nil {
-		writeError(w, 500, "UNKNOWN", err.Error())
-		return
-	}
+		if err := st.PullRequestStorage.CreatePullRequest(ctx, req.PRID, req.PRName, req.Author, status); err != nil {
+			return err
+		}
 
-	for _, rID := range assigned {
-		if err := h.Storage.PullRequestStorage.AddReviewer(ctx, req.PRID, rID); err != nil {
-			writeError(w, 500, "UNKNOWN", err.Error())
-			return
+		assigned := []string{}
+		reviews := []models.Review{}
+		for _, p := range picked {
+			if err := st.PullRequestStorage.AddReviewer(ctx, req.PRID, p.User.UserID, p.Seed); err != nil {
+				return err
+			}
+			assigned = append(assigned, p.User.UserID)
+			reviews = append(reviews, models.Review{
+				ReviewerID:     p.User.UserID,
+				State:          models.ReviewPending,
+				AssignmentSeed: p.Seed,
+			})
+		}
+
+		if err := recordAssignments(ctx, st, r, req.PRID, picked, "pull request created"); err != nil {
+			return err
+		}
+
+		respPR = models.PullRequest{
+			PullRequestID:     req.PRID,
+			PullRequestName:   req.PRName,
+			AuthorID:          req.Author,
+			Status:            status,
+			AssignedReviewers: assigned,
+			Reviews:           reviews,
+			CreatedAt:         nil,
+			MergedAt:          nil,
 		}
+
+		return nil
+	})
+	if err != nil {
+		writeErr(w, err)
+		return
 	}
 
-	respPR := models.PullRequest{
-		PullRequestID:     req.PRID,
-		PullRequestName:   req.PRName,
-		AuthorID:          req.Author,
-		Status:            "OPEN",
-		AssignedReviewers: assigned,
-		CreatedAt:         nil,
-		MergedAt:          nil,
+	status := http.StatusCreated
+	if req.DryRun {
+		status = http.StatusOK
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(map[string]any{
-		"pr": respPR,
+		"dry_run":            req.DryRun,
+		"pr":                 respPR,
+		"required_reviewers": team.RequiredReviewers,
+		"missing_reviewers":  missingReviewers(team.RequiredReviewers, len(respPR.AssignedReviewers)),
+		"fallback_reviewers": fallbackReviewers(picked),
 	})
 }
 
 func (h *Handler) MergePullRequest(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		PRID string `json:"pull_request_id"`
+		PRID   string `json:"pull_request_id"`
+		Reason string `json:"reason"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -310,54 +544,80 @@ func (h *Handler) MergePullRequest(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
-	pr, err := h.Storage.PullRequestStorage.GetPullRequestByID(ctx, req.PRID)
-	if errors.Is(err, storageErrors.ErrPRNotFound) {
-		writeError(w, http.StatusNotFound, "NOT_FOUND", "pull request not found")
-		return
-	}
-	if err != nil {
-		writeError(w, 500, "UNKNOWN", err.Error())
-		return
-	}
+	var resp models.PullRequest
 
-	reviewers, err := h.Storage.PullRequestStorage.GetReviewersByPR(ctx, req.PRID)
-	if err != nil {
-		writeError(w, 500, "UNKNOWN", err.Error())
-		return
-	}
+	err := h.Storage.WithTx(ctx, func(st *storage.Storage) error {
+		err := st.PullRequestStorage.LockPullRequest(ctx, req.PRID)
+		if errors.Is(err, storageErrors.ErrPRNotFound) {
+			return newAPIError(http.StatusNotFound, "NOT_FOUND", "pull request not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		pr, err := st.PullRequestStorage.GetPullRequestByID(ctx, req.PRID)
+		if err != nil {
+			return err
+		}
+
+		reviewers, err := st.PullRequestStorage.GetReviewersByPR(ctx, req.PRID)
+		if err != nil {
+			return err
+		}
+
+		if pr.Status == models.StatusMerged {
+			resp = models.PullRequest{
+				PullRequestID:     pr.PullRequestID,
+				PullRequestName:   pr.PullRequestName,
+				AuthorID:          pr.AuthorID,
+				Status:            pr.Status,
+				AssignedReviewers: reviewers,
+				Reviews:           pr.Reviews,
+				CreatedAt:         pr.CreatedAt,
+				MergedAt:          pr.MergedAt,
+			}
+			return nil
+		}
+
+		if !pr.Status.CanTransitionTo(models.StatusMerged) {
+			return invalidTransition(pr.Status, models.StatusMerged)
+		}
 
-	if pr.Status == "MERGED" {
-		resp := models.PullRequest{
+		if err := h.checkMergePolicy(ctx, st, pr); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		if err := st.PullRequestStorage.SetPullRequestStatus(ctx, req.PRID, models.StatusMerged, now); err != nil {
+			return err
+		}
+
+		if err := recordEvent(ctx, st, r, models.AssignmentEvent{
+			EventType:     models.EventMerge,
+			PullRequestID: req.PRID,
+			Reason:        req.Reason,
+		}); err != nil {
+			return err
+		}
+
+		resp = models.PullRequest{
 			PullRequestID:     pr.PullRequestID,
 			PullRequestName:   pr.PullRequestName,
 			AuthorID:          pr.AuthorID,
-			Status:            pr.Status,
+			Status:            models.StatusMerged,
 			AssignedReviewers: reviewers,
+			Reviews:           pr.Reviews,
 			CreatedAt:         pr.CreatedAt,
-			MergedAt:          pr.MergedAt,
+			MergedAt:          &now,
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]any{"pr": resp})
-		return
-	}
-
-	now := time.Now().UTC()
-	if err := h.Storage.PullRequestStorage.SetPullRequestStatus(ctx, req.PRID, "MERGED", now); err != nil {
-		writeError(w, 500, "UNKNOWN", err.Error())
+		return nil
+	})
+	if err != nil {
+		writeErr(w, err)
 		return
 	}
 
-	resp := models.PullRequest{
-		PullRequestID:     pr.PullRequestID,
-		PullRequestName:   pr.PullRequestName,
-		AuthorID:          pr.AuthorID,
-		Status:            "MERGED",
-		AssignedReviewers: reviewers,
-		CreatedAt:         pr.CreatedAt,
-		MergedAt:          &now,
-	}
-
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]any{"pr": resp})
 }
@@ -367,6 +627,8 @@ func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		PullRequestID string `json:"pull_request_id"`
 		OldUserID     string `json:"old_reviewer_id"`
+		Reason        string `json:"reason"`
+		DryRun        bool   `json:"dry_run"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -381,106 +643,94 @@ func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
-	pr, err := h.Storage.PullRequestStorage.GetPullRequestByID(ctx, req.PullRequestID)
-	if errors.Is(err, storageErrors.ErrPRNotFound) {
-		writeError(w, http.StatusNotFound, "NOT_FOUND", "pull request not found")
-		return
-	}
-	if err != nil {
-		writeError(w, 500, "UNKNOWN", err.Error())
-		return
-	}
-
-	if pr.Status == "MERGED" {
-		writeError(w, http.StatusConflict, "PR_MERGED", "cannot reassign on merged PR")
-		return
-	}
-
-	reviewers, err := h.Storage.PullRequestStorage.GetReviewersByPR(ctx, req.PullRequestID)
-	if err != nil {
-		writeError(w, 500, "UNKNOWN", err.Error())
-		return
-	}
+	var resp map[string]any
 
-	isAssigned := false
-	for _, id := range reviewers {
-		if id == req.OldUserID {
-			isAssigned = true
-			break
+	err := h.runTx(ctx, req.DryRun, func(st *storage.Storage) error {
+		err := st.PullRequestStorage.LockPullRequest(ctx, req.PullRequestID)
+		if errors.Is(err, storageErrors.ErrPRNotFound) {
+			return newAPIError(http.StatusNotFound, "NOT_FOUND", "pull request not found")
+		}
+		if err != nil {
+			return err
 		}
-	}
 
-	if !isAssigned {
-		writeError(w, http.StatusConflict, "NOT_ASSIGNED", "reviewer is not assigned to this PR")
-		return
-	}
+		pr, err := st.PullRequestStorage.GetPullRequestByID(ctx, req.PullRequestID)
+		if err != nil {
+			return err
+		}
 
-	user, err := h.Storage.UserStorage.GetUserByID(ctx, req.OldUserID)
-	if errors.Is(err, storageErrors.ErrUserNotFound) {
-		writeError(w, http.StatusNotFound, "NOT_FOUND", "old user not found")
-		return
-	}
-	if err != nil {
-		writeError(w, 500, "UNKNOWN", err.Error())
-		return
-	}
+		if pr.Status == models.StatusMerged {
+			return newAPIError(http.StatusConflict, "PR_MERGED", "cannot reassign on merged PR")
+		}
+		if pr.Status != models.StatusOpen {
+			return newAPIError(http.StatusConflict, "PR_NOT_OPEN", "cannot reassign on "+string(pr.Status)+" PR")
+		}
 
-	teamMembers, err := h.Storage.TeamStorage.GetUsersByTeam(ctx, user.TeamName)
-	if err != nil {
-		writeError(w, 500, "UNKNOWN", err.Error())
-		return
-	}
+		reviewers, err := st.PullRequestStorage.GetReviewersByPR(ctx, req.PullRequestID)
+		if err != nil {
+			return err
+		}
 
-	assignedSet := map[string]struct{}{}
-	for _, rid := range reviewers {
-		assignedSet[rid] = struct{}{}
-	}
+		isAssigned := false
+		for _, id := range reviewers {
+			if id == req.OldUserID {
+				isAssigned = true
+				break
+			}
+		}
 
-	var replacement *models.User
+		if !isAssigned {
+			return newAPIError(http.StatusConflict, "NOT_ASSIGNED", "reviewer is not assigned to this PR")
+		}
 
-	for _, m := range teamMembers {
-		if !m.IsActive {
-			continue
+		user, err := st.UserStorage.GetUserByID(ctx, req.OldUserID)
+		if errors.Is(err, storageErrors.ErrUserNotFound) {
+			return newAPIError(http.StatusNotFound, "NOT_FOUND", "old user not found")
 		}
-		if m.UserID == pr.AuthorID {
-			continue
+		if err != nil {
+			return err
 		}
-		if m.UserID == req.OldUserID {
-			continue
+
+		replacement, err := h.replaceReviewer(ctx, st, r, pr, user, req.Reason)
+		if err != nil {
+			return err
 		}
-		if _, exists := assignedSet[m.UserID]; exists {
-			continue
+		picked := []pickedReviewer{replacement}
+
+		updatedPR, err := st.PullRequestStorage.GetPullRequestByID(ctx, req.PullRequestID)
+		if err != nil {
+			return err
 		}
-		replacement = &m
-		break
-	}
 
-	if replacement == nil {
-		writeError(w, http.StatusConflict, "NO_CANDIDATE", "no active replacement candidate in team")
-		return
-	}
+		author, err := st.UserStorage.GetUserByID(ctx, pr.AuthorID)
+		if err != nil {
+			return err
+		}
 
-	if err := h.Storage.PullRequestStorage.RemoveReviewer(ctx, req.PullRequestID, req.OldUserID); err != nil {
-		writeError(w, 500, "UNKNOWN", err.Error())
-		return
-	}
+		team, err := st.TeamStorage.GetTeamByName(ctx, author.TeamName)
+		if err != nil {
+			return err
+		}
 
-	if err := h.Storage.PullRequestStorage.AddReviewer(ctx, req.PullRequestID, replacement.UserID); err != nil {
-		writeError(w, 500, "UNKNOWN", err.Error())
-		return
-	}
+		resp = map[string]any{
+			"dry_run":            req.DryRun,
+			"pr":                 updatedPR,
+			"replaced_by":        replacement.User.UserID,
+			"required_reviewers": team.RequiredReviewers,
+			"missing_reviewers":  missingReviewers(team.RequiredReviewers, len(updatedPR.AssignedReviewers)),
+			"fallback_reviewers": fallbackReviewers(picked),
+		}
+		if replacement.Seed != nil {
+			resp["assignment_seed"] = *replacement.Seed
+		}
 
-	updatedPR, err := h.Storage.PullRequestStorage.GetPullRequestByID(ctx, req.PullRequestID)
+		return nil
+	})
 	if err != nil {
-		writeError(w, 500, "UNKNOWN", err.Error())
+		writeErr(w, err)
 		return
 	}
 
-	resp := map[string]any{
-		"pr":          updatedPR,
-		"replaced_by": replacement.UserID,
-	}
-
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
@@ -492,6 +742,12 @@ func (h *Handler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	query, err := parsePullRequestQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+
 	ctx := r.Context()
 
 	user, err := h.Storage.UserStorage.GetUserByID(ctx, userID)
@@ -504,28 +760,72 @@ func (h *Handler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	prs, err := h.Storage.PullRequestStorage.GetPullRequestsByReviewer(ctx, userID)
+	// One extra row tells whether another page follows.
+	limit := query.Limit
+	query.Limit++
+
+	prs, err := h.Storage.PullRequestStorage.GetPullRequestsByReviewer(ctx, userID, query)
 	if err != nil {
 		writeError(w, 500, "UNKNOWN", err.Error())
 		return
 	}
 
+	prs, nextCursor := nextPage(prs, query, limit)
+
 	response := map[string]any{
 		"user_id":       user.UserID,
 		"pull_requests": prs,
+		"next_cursor":   nextCursor,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
-func NewHandler(storage *storage.Storage, log *slog.Logger) *Handler {
+func (h *Handler) ListPullRequests(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	query, err := parsePullRequestQuery(values)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+
+	query.TeamName = values.Get("team_name")
+	query.ReviewerID = values.Get("reviewer_id")
+	query.NameContains = values.Get("name")
+
+	limit := query.Limit
+	query.Limit++
+
+	prs, err := h.Storage.PullRequestStorage.ListPullRequests(r.Context(), query)
+	if err != nil {
+		writeError(w, 500, "UNKNOWN", err.Error())
+		return
+	}
+
+	prs, nextCursor := nextPage(prs, query, limit)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"pull_requests": prs,
+		"next_cursor":   nextCursor,
+	})
+}
+
+func NewHandler(storage *storage.Storage, selector assignment.ReviewerSelector, requireApprovals bool, log *slog.Logger) *Handler {
 	return &Handler{
-		Storage: storage,
-		Log:     log,
+		Storage:          storage,
+		Selector:         selector,
+		RequireApprovals: requireApprovals,
+		Log:              log,
 	}
 }
 
+func missingReviewers(required, assigned int) int {
+	return max(required-assigned, 0)
+}
+
 func writeError(w http.ResponseWriter, status int, code, msg string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -0,0 +1,264 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/assignment"
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage/memory"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage/sqlite"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage/storagetest"
+)
+
+var errInjected = errors.New("injected failure")
+
+// backends lists the storages that handler tests touching transactions run
+// against. Postgres is skipped unless storagetest.PostgresDSNEnv is set.
+var backends = []struct {
+	name       string
+	newStorage storagetest.Factory
+}{
+	{"Memory", func(t *testing.T) *storage.Storage { return memory.NewMemoryStorage() }},
+	{"SQLite", func(t *testing.T) *storage.Storage {
+		st, err := sqlite.NewSQLiteStorage(filepath.Join(t.TempDir(), "test.db"))
+		if err != nil {
+			t.Fatalf("NewSQLiteStorage: %v", err)
+		}
+		t.Cleanup(func() { st.Close() })
+		return st
+	}},
+	{"Postgres", storagetest.Postgres},
+}
+
+func newTestServer(st *storage.Storage, selector assignment.ReviewerSelector) http.Handler {
+	mux := http.NewServeMux()
+	NewHandler(st, selector, false, nil).RegisterRoutes(mux)
+
+	return mux
+}
+
+func do(t testing.TB, srv http.Handler, method, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatalf("encode body: %v", err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(method, path, &payload))
+
+	return rec
+}
+
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+
+	var v T
+	if err := json.NewDecoder(rec.Body).Decode(&v); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	return v
+}
+
+func mustStatus(t testing.TB, rec *httptest.ResponseRecorder, want int) {
+	t.Helper()
+
+	if rec.Code != want {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, want, rec.Body.String())
+	}
+}
+
+type member struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+}
+
+func mustAddTeam(t *testing.T, srv http.Handler, teamName string, requiredReviewers int, userIDs ...string) {
+	t.Helper()
+
+	members := make([]member, 0, len(userIDs))
+	for _, id := range userIDs {
+		members = append(members, member{UserID: id, Username: id, IsActive: true})
+	}
+
+	rec := do(t, srv, http.MethodPost, "/team/add", map[string]any{
+		"team_name":          teamName,
+		"required_reviewers": requiredReviewers,
+		"members":            members,
+	})
+	mustStatus(t, rec, http.StatusCreated)
+}
+
+// wrappingTransactor hands every transaction a copy of its storage changed by
+// wrap, e.g. to inject faults after some writes already happened.
+type wrappingTransactor struct {
+	storage.Transactor
+	wrap func(st *storage.Storage)
+}
+
+func (w wrappingTransactor) WithTx(ctx context.Context, fn func(st *storage.Storage) error) error {
+	return w.Transactor.WithTx(ctx, func(st *storage.Storage) error {
+		wrapped := *st
+		w.wrap(&wrapped)
+
+		return fn(&wrapped)
+	})
+}
+
+func wrapTx(st *storage.Storage, wrap func(st *storage.Storage)) {
+	st.Transactor = wrappingTransactor{Transactor: st.Transactor, wrap: wrap}
+}
+
+type failingAddReviewer struct{ storage.PullRequestStorage }
+
+func (failingAddReviewer) AddReviewer(context.Context, string, string, *int64) error {
+	return errInjected
+}
+
+type failingAddEvent struct{ storage.EventStorage }
+
+func (failingAddEvent) AddEvent(context.Context, models.AssignmentEvent) error {
+	return errInjected
+}
+
+type failingSetUserActiveStatus struct{ storage.UserStorage }
+
+func (failingSetUserActiveStatus) SetUserActiveStatus(context.Context, string, bool) error {
+	return errInjected
+}
+
+func assertNoPullRequest(t *testing.T, st *storage.Storage, prID string, reviewers ...string) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	if _, err := st.PullRequestStorage.GetPullRequestByID(ctx, prID); !errors.Is(err, storageErrors.ErrPRNotFound) {
+		t.Errorf("GetPullRequestByID(%q) err = %v, want ErrPRNotFound", prID, err)
+	}
+
+	for _, id := range reviewers {
+		prs, err := st.PullRequestStorage.GetPullRequestsByReviewer(ctx, id, models.PullRequestQuery{})
+		if err != nil {
+			t.Fatalf("GetPullRequestsByReviewer(%q): %v", id, err)
+		}
+		if len(prs) != 0 {
+			t.Errorf("%s still reviews %v", id, prs)
+		}
+	}
+}
+
+func TestCreatePullRequestRollsBack(t *testing.T) {
+	tests := []struct {
+		name string
+		wrap func(st *storage.Storage)
+	}{
+		{"AddReviewer", func(st *storage.Storage) {
+			st.PullRequestStorage = failingAddReviewer{st.PullRequestStorage}
+		}},
+		{"AddEvent", func(st *storage.Storage) {
+			st.EventStorage = failingAddEvent{st.EventStorage}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := memory.NewMemoryStorage()
+			srv := newTestServer(st, &assignment.FirstSelector{})
+			mustAddTeam(t, srv, "backend", 2, "u1", "u2", "u3")
+
+			wrapTx(st, tt.wrap)
+
+			rec := do(t, srv, http.MethodPost, "/pullRequest/create", map[string]any{
+				"pull_request_id":   "pr-1",
+				"pull_request_name": "Add search",
+				"author_id":         "u1",
+			})
+			mustStatus(t, rec, http.StatusInternalServerError)
+
+			assertNoPullRequest(t, st, "pr-1", "u2", "u3")
+		})
+	}
+}
+
+func TestReassignReviewerRollsBack(t *testing.T) {
+	tests := []struct {
+		name string
+		wrap func(st *storage.Storage)
+	}{
+		{"AddReviewer", func(st *storage.Storage) {
+			st.PullRequestStorage = failingAddReviewer{st.PullRequestStorage}
+		}},
+		{"AddEvent", func(st *storage.Storage) {
+			st.EventStorage = failingAddEvent{st.EventStorage}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := memory.NewMemoryStorage()
+			srv := newTestServer(st, &assignment.FirstSelector{})
+			mustAddTeam(t, srv, "backend", 1, "u1", "u2", "u3")
+
+			rec := do(t, srv, http.MethodPost, "/pullRequest/create", map[string]any{
+				"pull_request_id":   "pr-1",
+				"pull_request_name": "Add search",
+				"author_id":         "u1",
+			})
+			mustStatus(t, rec, http.StatusCreated)
+
+			wrapTx(st, tt.wrap)
+
+			rec = do(t, srv, http.MethodPost, "/pullRequest/reassign", map[string]any{
+				"pull_request_id": "pr-1",
+				"old_reviewer_id": "u2",
+			})
+			mustStatus(t, rec, http.StatusInternalServerError)
+
+			reviewers, err := st.PullRequestStorage.GetReviewersByPR(context.Background(), "pr-1")
+			if err != nil {
+				t.Fatalf("GetReviewersByPR: %v", err)
+			}
+			if len(reviewers) != 1 || reviewers[0] != "u2" {
+				t.Errorf("reviewers = %v, want [u2]", reviewers)
+			}
+		})
+	}
+}
+
+func TestCreateTeamRollsBack(t *testing.T) {
+	st := memory.NewMemoryStorage()
+	srv := newTestServer(st, &assignment.FirstSelector{})
+
+	wrapTx(st, func(st *storage.Storage) {
+		st.UserStorage = failingSetUserActiveStatus{st.UserStorage}
+	})
+
+	rec := do(t, srv, http.MethodPost, "/team/add", map[string]any{
+		"team_name": "backend",
+		"members":   []member{{UserID: "u1", Username: "u1", IsActive: false}},
+	})
+	mustStatus(t, rec, http.StatusInternalServerError)
+
+	ctx := context.Background()
+
+	if _, err := st.TeamStorage.GetTeamByName(ctx, "backend"); !errors.Is(err, storageErrors.ErrTeamNotFound) {
+		t.Errorf("GetTeamByName err = %v, want ErrTeamNotFound", err)
+	}
+	if _, err := st.UserStorage.GetUserByID(ctx, "u1"); !errors.Is(err, storageErrors.ErrUserNotFound) {
+		t.Errorf("GetUserByID err = %v, want ErrUserNotFound", err)
+	}
+}
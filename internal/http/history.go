@@ -0,0 +1,126 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
+)
+
+// actorHeader names who made a request. It is recorded as is in the audit
+// log; the service does no authentication of its own.
+const actorHeader = "X-Actor"
+
+func recordEvent(ctx context.Context, st *storage.Storage, r *http.Request, event models.AssignmentEvent) error {
+	event.Actor = r.Header.Get(actorHeader)
+	event.CreatedAt = time.Now().UTC()
+
+	return st.EventStorage.AddEvent(ctx, event)
+}
+
+// recordEvents logs events stamped like recordEvent with a single insert.
+func recordEvents(ctx context.Context, st *storage.Storage, r *http.Request, events []models.AssignmentEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	actor := r.Header.Get(actorHeader)
+	now := time.Now().UTC()
+	for i := range events {
+		events[i].Actor = actor
+		events[i].CreatedAt = now
+	}
+
+	return st.EventStorage.AddEvents(ctx, events)
+}
+
+// recordAssignments logs an ASSIGN event for every picked reviewer, noting
+// reviewers drawn from a fallback team.
+func recordAssignments(ctx context.Context, st *storage.Storage, r *http.Request, prID string, picked []pickedReviewer, reason string) error {
+	for _, p := range picked {
+		why := reason
+		if p.Fallback {
+			why += " (fallback team " + p.User.TeamName + ")"
+		}
+
+		if err := recordEvent(ctx, st, r, models.AssignmentEvent{
+			EventType:     models.EventAssign,
+			PullRequestID: prID,
+			UserID:        p.User.UserID,
+			Reason:        why,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *Handler) GetPullRequestHistory(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "missing pull_request_id")
+		return
+	}
+
+	ctx := r.Context()
+
+	_, err := h.Storage.PullRequestStorage.GetPullRequestByID(ctx, prID)
+	if errors.Is(err, storageErrors.ErrPRNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "pull request not found")
+		return
+	}
+	if err != nil {
+		writeError(w, 500, "UNKNOWN", err.Error())
+		return
+	}
+
+	events, err := h.Storage.EventStorage.GetEventsByPR(ctx, prID)
+	if err != nil {
+		writeError(w, 500, "UNKNOWN", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"pull_request_id": prID,
+		"events":          events,
+	})
+}
+
+func (h *Handler) GetUserHistory(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "missing user_id")
+		return
+	}
+
+	ctx := r.Context()
+
+	_, err := h.Storage.UserStorage.GetUserByID(ctx, userID)
+	if errors.Is(err, storageErrors.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "user not found")
+		return
+	}
+	if err != nil {
+		writeError(w, 500, "UNKNOWN", err.Error())
+		return
+	}
+
+	events, err := h.Storage.EventStorage.GetEventsByUser(ctx, userID)
+	if err != nil {
+		writeError(w, 500, "UNKNOWN", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"user_id": userID,
+		"events":  events,
+	})
+}
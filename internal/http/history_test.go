@@ -0,0 +1,53 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/assignment"
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage/memory"
+)
+
+func TestCreateTeamRecordsActivation(t *testing.T) {
+	st := memory.NewMemoryStorage()
+	srv := newTestServer(st, &assignment.FirstSelector{})
+
+	rec := do(t, srv, http.MethodPost, "/team/add", map[string]any{
+		"team_name": "backend",
+		"members": []member{
+			{UserID: "u1", Username: "u1", IsActive: true},
+			{UserID: "u2", Username: "u2", IsActive: false},
+		},
+	})
+	mustStatus(t, rec, http.StatusCreated)
+
+	rec = do(t, srv, http.MethodPost, "/team/add", map[string]any{
+		"team_name": "platform",
+		"members":   []member{{UserID: "u2", Username: "u2", IsActive: true}},
+	})
+	mustStatus(t, rec, http.StatusCreated)
+
+	want := map[string][]models.EventType{
+		"u1": nil,
+		"u2": {models.EventDeactivate, models.EventActivate},
+	}
+
+	for userID, types := range want {
+		events, err := st.EventStorage.GetEventsByUser(context.Background(), userID)
+		if err != nil {
+			t.Fatalf("GetEventsByUser(%q): %v", userID, err)
+		}
+
+		var got []models.EventType
+		for _, e := range events {
+			got = append(got, e.EventType)
+		}
+
+		if !reflect.DeepEqual(got, types) {
+			t.Errorf("%s events = %v, want %v", userID, got, types)
+		}
+	}
+}
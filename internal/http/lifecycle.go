@@ -0,0 +1,160 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
+)
+
+func (h *Handler) ClosePullRequest(w http.ResponseWriter, r *http.Request) {
+	h.transitionPullRequest(w, r, models.StatusClosed, nil)
+}
+
+func (h *Handler) ReopenPullRequest(w http.ResponseWriter, r *http.Request) {
+	h.transitionPullRequest(w, r, models.StatusOpen, requireStatus(models.StatusClosed, "PR_NOT_CLOSED", "pull request is not closed"))
+}
+
+func (h *Handler) MarkPullRequestReady(w http.ResponseWriter, r *http.Request) {
+	h.transitionPullRequest(w, r, models.StatusOpen, requireStatus(models.StatusDraft, "PR_NOT_DRAFT", "pull request is not a draft"))
+}
+
+func requireStatus(status models.PullRequestStatus, code, msg string) func(pr models.PullRequest) error {
+	return func(pr models.PullRequest) error {
+		if pr.Status != status {
+			return newAPIError(http.StatusConflict, code, msg)
+		}
+		return nil
+	}
+}
+
+// transitionPullRequest moves a pull request to next after check accepts its
+// current state. A pull request that becomes OPEN gets reviewers topped up to
+// its team's required count, which is where drafts receive their first
+// assignment.
+func (h *Handler) transitionPullRequest(
+	w http.ResponseWriter,
+	r *http.Request,
+	next models.PullRequestStatus,
+	check func(pr models.PullRequest) error,
+) {
+	var req struct {
+		PRID string `json:"pull_request_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+
+	if req.PRID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "missing pull_request_id")
+		return
+	}
+
+	ctx := r.Context()
+	prID := req.PRID
+
+	var resp map[string]any
+
+	err := h.Storage.WithTx(ctx, func(st *storage.Storage) error {
+		err := st.PullRequestStorage.LockPullRequest(ctx, prID)
+		if errors.Is(err, storageErrors.ErrPRNotFound) {
+			return newAPIError(http.StatusNotFound, "NOT_FOUND", "pull request not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		pr, err := st.PullRequestStorage.GetPullRequestByID(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		if check != nil {
+			if err := check(pr); err != nil {
+				return err
+			}
+		}
+
+		if !pr.Status.CanTransitionTo(next) {
+			return invalidTransition(pr.Status, next)
+		}
+
+		if err := st.PullRequestStorage.SetPullRequestStatus(ctx, prID, next, time.Now().UTC()); err != nil {
+			return err
+		}
+
+		author, err := st.UserStorage.GetUserByID(ctx, pr.AuthorID)
+		if err != nil {
+			return err
+		}
+
+		team, err := st.TeamStorage.GetTeamByName(ctx, author.TeamName)
+		if err != nil {
+			return err
+		}
+
+		picked := []pickedReviewer{}
+		if next == models.StatusOpen {
+			assigned := map[string]struct{}{}
+			for _, id := range pr.AssignedReviewers {
+				assigned[id] = struct{}{}
+			}
+
+			picked, err = h.pickReviewers(ctx, st, team, prID, pr.AuthorID, assigned, team.RequiredReviewers-len(assigned))
+			if err != nil {
+				return err
+			}
+
+			for _, p := range picked {
+				if err := st.PullRequestStorage.AddReviewer(ctx, prID, p.User.UserID, p.Seed); err != nil {
+					return err
+				}
+			}
+
+			reason := "pull request reopened"
+			if pr.Status == models.StatusDraft {
+				reason = "pull request ready for review"
+			}
+
+			if err := recordAssignments(ctx, st, r, prID, picked, reason); err != nil {
+				return err
+			}
+		}
+
+		updated, err := st.PullRequestStorage.GetPullRequestByID(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		resp = map[string]any{
+			"pr":                 updated,
+			"required_reviewers": team.RequiredReviewers,
+			"missing_reviewers":  missingReviewers(team.RequiredReviewers, len(updated.AssignedReviewers)),
+			"fallback_reviewers": fallbackReviewers(picked),
+		}
+
+		return nil
+	})
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func invalidTransition(from, to models.PullRequestStatus) error {
+	return newAPIError(
+		http.StatusConflict,
+		"INVALID_TRANSITION",
+		fmt.Sprintf("cannot move pull request from %s to %s", from, to),
+	)
+}
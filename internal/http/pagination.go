@@ -0,0 +1,130 @@
+package http
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// parsePullRequestQuery reads limit, cursor, sort, order, status, author_id,
+// created_from and created_to. status is a comma separated list.
+func parsePullRequestQuery(values url.Values) (models.PullRequestQuery, error) {
+	query := models.PullRequestQuery{Limit: defaultPageLimit}
+
+	switch sort := models.PullRequestSort(values.Get("sort")); sort {
+	case "", models.SortByCreatedAt:
+		query.Sort = models.SortByCreatedAt
+	case models.SortByName:
+		query.Sort = sort
+	default:
+		return models.PullRequestQuery{}, fmt.Errorf("sort must be %q or %q", models.SortByCreatedAt, models.SortByName)
+	}
+
+	switch values.Get("order") {
+	case "", "asc":
+	case "desc":
+		query.Desc = true
+	default:
+		return models.PullRequestQuery{}, errors.New(`order must be "asc" or "desc"`)
+	}
+
+	if v := values.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > maxPageLimit {
+			return models.PullRequestQuery{}, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		query.Limit = limit
+	}
+
+	if v := values.Get("cursor"); v != "" {
+		after, err := decodeCursor(v, query)
+		if err != nil {
+			return models.PullRequestQuery{}, errors.New("invalid cursor")
+		}
+		query.After = &after
+	}
+
+	if v := values.Get("status"); v != "" {
+		for _, s := range strings.Split(v, ",") {
+			status := models.PullRequestStatus(strings.TrimSpace(s))
+			switch status {
+			case models.StatusDraft, models.StatusOpen, models.StatusClosed, models.StatusMerged:
+				query.Statuses = append(query.Statuses, status)
+			default:
+				return models.PullRequestQuery{}, fmt.Errorf("unknown status %q", s)
+			}
+		}
+	}
+
+	query.AuthorID = values.Get("author_id")
+
+	var err error
+	if query.CreatedFrom, err = parseTimeParam(values.Get("created_from")); err != nil {
+		return models.PullRequestQuery{}, errors.New("created_from must be an RFC 3339 timestamp")
+	}
+	if query.CreatedTo, err = parseTimeParam(values.Get("created_to")); err != nil {
+		return models.PullRequestQuery{}, errors.New("created_to must be an RFC 3339 timestamp")
+	}
+
+	return query, nil
+}
+
+// pageToken is the opaque cursor handed to clients. It carries the order it
+// was issued for, so it cannot be replayed against another one.
+type pageToken struct {
+	Sort models.PullRequestSort `json:"sort"`
+	Desc bool                   `json:"desc,omitempty"`
+	models.PullRequestCursor
+}
+
+// nextPage trims prs, fetched with one row over limit, to the page and
+// returns the cursor of the following page, or nil on the last one.
+func nextPage(prs []models.PullRequestShort, query models.PullRequestQuery, limit int) ([]models.PullRequestShort, *string) {
+	if len(prs) <= limit {
+		return prs, nil
+	}
+
+	prs = prs[:limit]
+	last := prs[limit-1]
+
+	data, _ := json.Marshal(pageToken{
+		Sort: query.Sort,
+		Desc: query.Desc,
+		PullRequestCursor: models.PullRequestCursor{
+			CreatedAt:       last.CreatedAt,
+			PullRequestName: last.PullRequestName,
+			PullRequestID:   last.PullRequestID,
+		},
+	})
+	cursor := base64.RawURLEncoding.EncodeToString(data)
+
+	return prs, &cursor
+}
+
+func decodeCursor(cursor string, query models.PullRequestQuery) (models.PullRequestCursor, error) {
+	var token pageToken
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return models.PullRequestCursor{}, err
+	}
+	if err := json.Unmarshal(data, &token); err != nil {
+		return models.PullRequestCursor{}, err
+	}
+	if token.PullRequestID == "" || token.Sort != query.Sort || token.Desc != query.Desc {
+		return models.PullRequestCursor{}, errors.New("cursor does not match the query")
+	}
+
+	return token.PullRequestCursor, nil
+}
@@ -0,0 +1,146 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/assignment"
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+)
+
+// TestReassignReviewerConcurrent hammers one pull request with reassignments
+// and checks that the row lock neither loses nor duplicates reviewers.
+func TestReassignReviewerConcurrent(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			testReassignReviewerConcurrent(t, b.newStorage(t))
+		})
+	}
+}
+
+func testReassignReviewerConcurrent(t *testing.T, st *storage.Storage) {
+	const (
+		workers           = 16
+		attempts          = 10
+		requiredReviewers = 3
+	)
+
+	srv := httptest.NewServer(newTestServer(st, assignment.NewRandomSelector(nil)))
+	defer srv.Close()
+
+	userIDs := make([]string, 0, 10)
+	for i := range 10 {
+		userIDs = append(userIDs, fmt.Sprintf("u%d", i))
+	}
+	mustAddTeam(t, srv.Config.Handler, "backend", requiredReviewers, userIDs...)
+
+	rec := do(t, srv.Config.Handler, http.MethodPost, "/pullRequest/create", map[string]any{
+		"pull_request_id":   "pr-1",
+		"pull_request_name": "Add search",
+		"author_id":         "u0",
+	})
+	mustStatus(t, rec, http.StatusCreated)
+
+	ctx := context.Background()
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		reassigned int
+		failures   []string
+	)
+
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range attempts {
+				reviewers, err := st.PullRequestStorage.GetReviewersByPR(ctx, "pr-1")
+				if err != nil || len(reviewers) == 0 {
+					mu.Lock()
+					failures = append(failures, fmt.Sprintf("GetReviewersByPR: %v %v", reviewers, err))
+					mu.Unlock()
+					return
+				}
+
+				body, _ := json.Marshal(map[string]any{
+					"pull_request_id": "pr-1",
+					"old_reviewer_id": reviewers[(w+i)%len(reviewers)],
+				})
+
+				resp, err := http.Post(srv.URL+"/pullRequest/reassign", "application/json", bytes.NewReader(body))
+				if err != nil {
+					mu.Lock()
+					failures = append(failures, err.Error())
+					mu.Unlock()
+					return
+				}
+				resp.Body.Close()
+
+				mu.Lock()
+				switch resp.StatusCode {
+				case http.StatusOK:
+					reassigned++
+				case http.StatusConflict:
+					// The reviewer was replaced by another worker meanwhile.
+				default:
+					failures = append(failures, fmt.Sprintf("status %d", resp.StatusCode))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for _, f := range failures {
+		t.Error(f)
+	}
+
+	reviewers, err := st.PullRequestStorage.GetReviewersByPR(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetReviewersByPR: %v", err)
+	}
+
+	if len(reviewers) != requiredReviewers {
+		t.Errorf("reviewers = %v, want %d of them", reviewers, requiredReviewers)
+	}
+
+	seen := map[string]struct{}{}
+	for _, id := range reviewers {
+		if id == "u0" {
+			t.Errorf("author is a reviewer: %v", reviewers)
+		}
+		if _, dup := seen[id]; dup {
+			t.Errorf("duplicate reviewer %s: %v", id, reviewers)
+		}
+		seen[id] = struct{}{}
+	}
+
+	events, err := st.EventStorage.GetEventsByPR(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetEventsByPR: %v", err)
+	}
+
+	logged := 0
+	for _, e := range events {
+		if e.EventType == models.EventReassign {
+			logged++
+		}
+	}
+
+	if reassigned == 0 {
+		t.Error("no reassignment succeeded")
+	}
+	if logged != reassigned {
+		t.Errorf("%d REASSIGN events for %d successful reassignments", logged, reassigned)
+	}
+}
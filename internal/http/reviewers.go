@@ -0,0 +1,190 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/assignment"
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
+)
+
+type pickedReviewer struct {
+	User     models.User
+	Seed     *int64
+	Fallback bool
+}
+
+// pickReviewers selects up to count reviewers, drawing from team first and
+// then from its fallback teams in order. The author and users in exclude are
+// never picked.
+func (h *Handler) pickReviewers(
+	ctx context.Context,
+	st *storage.Storage,
+	team models.Team,
+	prID, authorID string,
+	exclude map[string]struct{},
+	count int,
+) ([]pickedReviewer, error) {
+	skip := map[string]struct{}{authorID: {}}
+	for id := range exclude {
+		skip[id] = struct{}{}
+	}
+
+	picked := []pickedReviewer{}
+	teams := append([]string{team.TeamName}, team.FallbackTeams...)
+
+	for i, teamName := range teams {
+		need := count - len(picked)
+		if need <= 0 {
+			break
+		}
+
+		members, err := st.UserStorage.GetActiveUsersByTeam(ctx, teamName)
+		if err != nil {
+			return nil, err
+		}
+
+		candidates := make([]models.User, 0, len(members))
+		for _, m := range members {
+			if _, skipped := skip[m.UserID]; skipped {
+				continue
+			}
+			candidates = append(candidates, m)
+		}
+
+		if len(candidates) == 0 {
+			continue
+		}
+
+		selection, err := h.Selector.Select(ctx, st, assignment.Request{
+			PullRequestID: prID,
+			AuthorID:      authorID,
+			TeamName:      teamName,
+			Candidates:    candidates,
+			Count:         need,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, u := range selection.Reviewers {
+			picked = append(picked, pickedReviewer{
+				User:     u,
+				Seed:     selection.Seed,
+				Fallback: i > 0,
+			})
+			skip[u.UserID] = struct{}{}
+		}
+	}
+
+	return picked, nil
+}
+
+// replaceReviewer swaps old for an active reviewer from old's team or its
+// fallback teams and logs the reassignment. pr must be locked and OPEN.
+func (h *Handler) replaceReviewer(
+	ctx context.Context,
+	st *storage.Storage,
+	r *http.Request,
+	pr models.PullRequest,
+	old models.User,
+	reason string,
+) (pickedReviewer, error) {
+	oldTeam, err := st.TeamStorage.GetTeamByName(ctx, old.TeamName)
+	if err != nil {
+		return pickedReviewer{}, err
+	}
+
+	assigned := map[string]struct{}{}
+	for _, id := range pr.AssignedReviewers {
+		assigned[id] = struct{}{}
+	}
+
+	picked, err := h.pickReviewers(ctx, st, oldTeam, pr.PullRequestID, pr.AuthorID, assigned, 1)
+	if err != nil {
+		return pickedReviewer{}, err
+	}
+
+	if len(picked) == 0 {
+		return pickedReviewer{}, newAPIError(http.StatusConflict, "NO_CANDIDATE", "no active replacement candidate in team or fallback teams")
+	}
+
+	replacement := picked[0]
+
+	if err := applyReplacement(ctx, st, r, pr.PullRequestID, old.UserID, replacement, reason); err != nil {
+		return pickedReviewer{}, err
+	}
+
+	return replacement, nil
+}
+
+// applyReplacement swaps oldID for replacement on the pull request and logs
+// the reassignment.
+func applyReplacement(
+	ctx context.Context,
+	st *storage.Storage,
+	r *http.Request,
+	prID, oldID string,
+	replacement pickedReviewer,
+	reason string,
+) error {
+	if err := st.PullRequestStorage.RemoveReviewer(ctx, prID, oldID); err != nil {
+		return err
+	}
+
+	if err := st.PullRequestStorage.AddReviewer(ctx, prID, replacement.User.UserID, replacement.Seed); err != nil {
+		return err
+	}
+
+	return recordEvent(ctx, st, r, models.AssignmentEvent{
+		EventType:     models.EventReassign,
+		PullRequestID: prID,
+		UserID:        oldID,
+		ReplacementID: replacement.User.UserID,
+		Reason:        reason,
+	})
+}
+
+func fallbackReviewers(picked []pickedReviewer) []map[string]string {
+	result := []map[string]string{}
+	for _, p := range picked {
+		if p.Fallback {
+			result = append(result, map[string]string{
+				"user_id":   p.User.UserID,
+				"team_name": p.User.TeamName,
+			})
+		}
+	}
+
+	return result
+}
+
+// checkFallbackTeams rejects fallback lists that reference the team itself,
+// repeat a team or name an unknown team.
+func checkFallbackTeams(ctx context.Context, st *storage.Storage, teamName string, fallbackTeams []string) error {
+	seen := map[string]struct{}{}
+
+	for _, name := range fallbackTeams {
+		if name == teamName {
+			return newAPIError(http.StatusBadRequest, "BAD_REQUEST", "team cannot fall back to itself")
+		}
+		if _, dup := seen[name]; dup {
+			return newAPIError(http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("fallback team %q is listed twice", name))
+		}
+		seen[name] = struct{}{}
+
+		_, err := st.TeamStorage.GetTeamByName(ctx, name)
+		if errors.Is(err, storageErrors.ErrTeamNotFound) {
+			return newAPIError(http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("fallback team %q not found", name))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
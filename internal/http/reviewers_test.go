@@ -0,0 +1,76 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/assignment"
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage/memory"
+)
+
+// TestCreatePullRequestRecordsSeeds checks that every randomized draw,
+// including the one from a fallback team, is stored with its seed and can be
+// replayed with assignment.Shuffle.
+func TestCreatePullRequestRecordsSeeds(t *testing.T) {
+	var next int64
+	selector := assignment.NewRandomSelector(func() int64 {
+		next++
+		return next
+	})
+
+	st := memory.NewMemoryStorage()
+	srv := newTestServer(st, selector)
+
+	mustAddTeam(t, srv, "platform", 2, "p1", "p2", "p3", "p4")
+
+	rec := do(t, srv, http.MethodPost, "/team/add", map[string]any{
+		"team_name":          "backend",
+		"required_reviewers": 3,
+		"fallback_teams":     []string{"platform"},
+		"members": []member{
+			{UserID: "u1", Username: "u1", IsActive: true},
+			{UserID: "u2", Username: "u2", IsActive: true},
+			{UserID: "u3", Username: "u3", IsActive: true},
+		},
+	})
+	mustStatus(t, rec, http.StatusCreated)
+
+	rec = do(t, srv, http.MethodPost, "/pullRequest/create", map[string]any{
+		"pull_request_id":   "pr-1",
+		"pull_request_name": "Add search",
+		"author_id":         "u1",
+	})
+	mustStatus(t, rec, http.StatusCreated)
+
+	created := decode[struct {
+		PR models.PullRequest `json:"pr"`
+	}](t, rec).PR
+
+	platform, err := st.UserStorage.GetActiveUsersByTeam(context.Background(), "platform")
+	if err != nil {
+		t.Fatalf("GetActiveUsersByTeam: %v", err)
+	}
+	fallbackID := assignment.Shuffle(platform, 2)[0].UserID
+
+	want := map[string]int64{"u2": 1, "u3": 1, fallbackID: 2}
+
+	stored, err := st.PullRequestStorage.GetPullRequestByID(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("GetPullRequestByID: %v", err)
+	}
+
+	for name, reviews := range map[string][]models.Review{"response": created.Reviews, "storage": stored.Reviews} {
+		if len(reviews) != len(want) {
+			t.Fatalf("%s reviews = %+v, want seeds %v", name, reviews, want)
+		}
+
+		for _, r := range reviews {
+			seed, ok := want[r.ReviewerID]
+			if !ok || r.AssignmentSeed == nil || *r.AssignmentSeed != seed {
+				t.Errorf("%s review %+v, want seeds %v", name, r, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/mergepolicy"
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
+)
+
+func (h *Handler) SubmitReview(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PRID       string             `json:"pull_request_id"`
+		ReviewerID string             `json:"reviewer_id"`
+		State      models.ReviewState `json:"state"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+
+	if req.PRID == "" || req.ReviewerID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "missing required fields")
+		return
+	}
+
+	if !req.State.IsVerdict() {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "state must be APPROVED, CHANGES_REQUESTED or COMMENTED")
+		return
+	}
+
+	ctx := r.Context()
+
+	var resp models.PullRequest
+
+	err := h.Storage.WithTx(ctx, func(st *storage.Storage) error {
+		err := st.PullRequestStorage.LockPullRequest(ctx, req.PRID)
+		if errors.Is(err, storageErrors.ErrPRNotFound) {
+			return newAPIError(http.StatusNotFound, "NOT_FOUND", "pull request not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		pr, err := st.PullRequestStorage.GetPullRequestByID(ctx, req.PRID)
+		if err != nil {
+			return err
+		}
+
+		if pr.Status != models.StatusOpen {
+			return newAPIError(http.StatusConflict, "PR_NOT_OPEN", "cannot review "+string(pr.Status)+" PR")
+		}
+
+		now := time.Now().UTC()
+		if req.ReviewerID == pr.AuthorID {
+			// The author's verdict is recorded so that merge policies can
+			// refuse it as the only approval.
+			err = st.PullRequestStorage.SetAuthorReviewState(ctx, req.PRID, req.State, now)
+		} else {
+			err = st.PullRequestStorage.SetReviewState(ctx, req.PRID, req.ReviewerID, req.State, now)
+		}
+		if errors.Is(err, storageErrors.ErrReviewerNotAssigned) {
+			return newAPIError(http.StatusConflict, "NOT_ASSIGNED", "reviewer is not assigned to this PR")
+		}
+		if err != nil {
+			return err
+		}
+
+		resp, err = st.PullRequestStorage.GetPullRequestByID(ctx, req.PRID)
+		return err
+	})
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"pr": resp})
+}
+
+// checkMergePolicy evaluates the author's team merge policy and rejects the
+// merge with every unmet rule. RequireApprovals raises the policy's approval
+// minimum to the team's required reviewer count.
+func (h *Handler) checkMergePolicy(ctx context.Context, st *storage.Storage, pr models.PullRequest) error {
+	author, err := st.UserStorage.GetUserByID(ctx, pr.AuthorID)
+	if err != nil {
+		return err
+	}
+
+	team, err := st.TeamStorage.GetTeamByName(ctx, author.TeamName)
+	if err != nil {
+		return err
+	}
+
+	policy := team.MergePolicy
+	if h.RequireApprovals {
+		policy.MinApprovals = max(policy.MinApprovals, team.RequiredReviewers)
+	}
+
+	violations := mergepolicy.Evaluate(policy, pr)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	blocked := newAPIError(http.StatusConflict, "MERGE_BLOCKED", "merge policy not satisfied")
+	blocked.details = map[string]any{"unmet_rules": violations}
+
+	return blocked
+}
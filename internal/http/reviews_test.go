@@ -0,0 +1,104 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/assignment"
+	"github.com/pacahar/pr-reviewer-assignment/internal/mergepolicy"
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage/memory"
+)
+
+// newReviewedPR creates team backend with members u1 and u2 under policy and
+// opens pr-1 authored by u1, so u2 is its only assigned reviewer.
+func newReviewedPR(t *testing.T, policy models.MergePolicy) http.Handler {
+	t.Helper()
+
+	srv := newTestServer(memory.NewMemoryStorage(), &assignment.FirstSelector{})
+
+	rec := do(t, srv, http.MethodPost, "/team/add", map[string]any{
+		"team_name":          "backend",
+		"required_reviewers": 1,
+		"merge_policy":       policy,
+		"members": []member{
+			{UserID: "u1", Username: "u1", IsActive: true},
+			{UserID: "u2", Username: "u2", IsActive: true},
+		},
+	})
+	mustStatus(t, rec, http.StatusCreated)
+
+	rec = do(t, srv, http.MethodPost, "/pullRequest/create", map[string]any{
+		"pull_request_id":   "pr-1",
+		"pull_request_name": "Add search",
+		"author_id":         "u1",
+	})
+	mustStatus(t, rec, http.StatusCreated)
+
+	return srv
+}
+
+func approve(t *testing.T, srv http.Handler, reviewerID string) {
+	t.Helper()
+
+	rec := do(t, srv, http.MethodPost, "/pullRequest/review", map[string]any{
+		"pull_request_id": "pr-1",
+		"reviewer_id":     reviewerID,
+		"state":           models.ReviewApproved,
+	})
+	mustStatus(t, rec, http.StatusOK)
+}
+
+func mustMergeBlocked(t *testing.T, rec *httptest.ResponseRecorder, rules ...string) {
+	t.Helper()
+
+	mustStatus(t, rec, http.StatusConflict)
+
+	blocked := decode[struct {
+		Error struct {
+			Code       string                  `json:"code"`
+			UnmetRules []mergepolicy.Violation `json:"unmet_rules"`
+		} `json:"error"`
+	}](t, rec).Error
+
+	got := make([]string, 0, len(blocked.UnmetRules))
+	for _, v := range blocked.UnmetRules {
+		got = append(got, v.Rule)
+	}
+
+	if blocked.Code != "MERGE_BLOCKED" || !slices.Equal(got, rules) {
+		t.Fatalf("merge error = %+v, want MERGE_BLOCKED with %v", blocked, rules)
+	}
+}
+
+func TestMergeBlockedWhenAuthorIsSoleApprover(t *testing.T) {
+	srv := newReviewedPR(t, models.MergePolicy{MinApprovals: 1, ForbidAuthorSoleApprover: true})
+	merge := map[string]any{"pull_request_id": "pr-1"}
+
+	approve(t, srv, "u1")
+
+	rec := do(t, srv, http.MethodPost, "/pullRequest/merge", merge)
+	mustMergeBlocked(t, rec, mergepolicy.RuleMinApprovals, mergepolicy.RuleAuthorNotSoleApprover)
+
+	approve(t, srv, "u2")
+
+	rec = do(t, srv, http.MethodPost, "/pullRequest/merge", merge)
+	mustStatus(t, rec, http.StatusOK)
+}
+
+func TestSelfApprovalDoesNotCountTowardMinApprovals(t *testing.T) {
+	srv := newReviewedPR(t, models.MergePolicy{MinApprovals: 1})
+	merge := map[string]any{"pull_request_id": "pr-1"}
+
+	approve(t, srv, "u1")
+
+	rec := do(t, srv, http.MethodPost, "/pullRequest/merge", merge)
+	mustMergeBlocked(t, rec, mergepolicy.RuleMinApprovals)
+
+	approve(t, srv, "u2")
+
+	rec = do(t, srv, http.MethodPost, "/pullRequest/merge", merge)
+	mustStatus(t, rec, http.StatusOK)
+}
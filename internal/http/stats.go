@@ -0,0 +1,181 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
+)
+
+func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := models.StatsFilter{TeamName: query.Get("team_name")}
+
+	var err error
+	if filter.From, err = parseTimeParam(query.Get("from")); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "from must be an RFC 3339 timestamp")
+		return
+	}
+	if filter.To, err = parseTimeParam(query.Get("to")); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "to must be an RFC 3339 timestamp")
+		return
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "from must be before to")
+		return
+	}
+
+	ctx := r.Context()
+
+	if filter.TeamName != "" {
+		_, err := h.Storage.TeamStorage.GetTeamByName(ctx, filter.TeamName)
+		if errors.Is(err, storageErrors.ErrTeamNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "team not found")
+			return
+		}
+		if err != nil {
+			writeError(w, 500, "UNKNOWN", err.Error())
+			return
+		}
+	}
+
+	users, err := h.Storage.StatsStorage.GetUserStats(ctx, filter)
+	if err != nil {
+		writeError(w, 500, "UNKNOWN", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"team_name": filter.TeamName,
+		"from":      filter.From,
+		"to":        filter.To,
+		"users":     users,
+		"teams":     teamStats(users),
+	})
+}
+
+const (
+	defaultFairnessWindowDays = 30
+	defaultOverloadFactor     = 1.5
+)
+
+// GetFairness reports how evenly reviews on pull requests created in the
+// last window_days are spread over the team's active members. A member is
+// overloaded when their load exceeds overload_factor times the team mean.
+func (h *Handler) GetFairness(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	teamName := query.Get("team_name")
+	if teamName == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "missing team_name")
+		return
+	}
+
+	windowDays := defaultFairnessWindowDays
+	if v := query.Get("window_days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "BAD_REQUEST", "window_days must be a positive integer")
+			return
+		}
+		windowDays = n
+	}
+
+	factor := defaultOverloadFactor
+	if v := query.Get("overload_factor"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 1 {
+			writeError(w, http.StatusBadRequest, "BAD_REQUEST", "overload_factor must be a number >= 1")
+			return
+		}
+		factor = f
+	}
+
+	ctx := r.Context()
+
+	_, err := h.Storage.TeamStorage.GetTeamByName(ctx, teamName)
+	if errors.Is(err, storageErrors.ErrTeamNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "team not found")
+		return
+	}
+	if err != nil {
+		writeError(w, 500, "UNKNOWN", err.Error())
+		return
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -windowDays)
+
+	dist, err := h.Storage.StatsStorage.GetReviewLoad(ctx, teamName, since)
+	if err != nil {
+		writeError(w, 500, "UNKNOWN", err.Error())
+		return
+	}
+
+	overloaded := []string{}
+	for i, u := range dist.Users {
+		if dist.Mean > 0 && float64(u.Reviews) > factor*dist.Mean {
+			dist.Users[i].Overloaded = true
+			overloaded = append(overloaded, u.UserID)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"team_name":        teamName,
+		"window_days":      windowDays,
+		"window_start":     since,
+		"overload_factor":  factor,
+		"distribution":     dist,
+		"overloaded_users": overloaded,
+	})
+}
+
+// teamStats sums user statistics per team. The team average time to merge
+// is weighted by each member's merged reviews. users must be ordered by team.
+func teamStats(users []models.UserStats) []models.TeamStats {
+	result := []models.TeamStats{}
+
+	var mergeSeconds float64
+	for _, u := range users {
+		if len(result) == 0 || result[len(result)-1].TeamName != u.TeamName {
+			mergeSeconds = 0
+			result = append(result, models.TeamStats{TeamName: u.TeamName})
+		}
+
+		t := &result[len(result)-1]
+		t.Members++
+		t.TotalAssignments += u.TotalAssignments
+		t.OpenReviews += u.OpenReviews
+		t.MergedReviews += u.MergedReviews
+		t.ReassignedIn += u.ReassignedIn
+		t.ReassignedOut += u.ReassignedOut
+
+		if u.AvgTimeToMergeSeconds != nil {
+			mergeSeconds += *u.AvgTimeToMergeSeconds * float64(u.MergedReviews)
+			avg := mergeSeconds / float64(t.MergedReviews)
+			t.AvgTimeToMergeSeconds = &avg
+		}
+	}
+
+	return result
+}
+
+func parseTimeParam(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+
+	t = t.UTC()
+	return &t, nil
+}
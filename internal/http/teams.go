@@ -0,0 +1,292 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
+)
+
+func (h *Handler) ListTeams(w http.ResponseWriter, r *http.Request) {
+	teams, err := h.Storage.TeamStorage.ListTeams(r.Context())
+	if err != nil {
+		writeError(w, 500, "UNKNOWN", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"teams": teams})
+}
+
+// AddTeamMember creates the user in the team or moves an existing user into
+// it. Their pull requests and reviews are kept.
+func (h *Handler) AddTeamMember(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName string `json:"team_name"`
+		UserID   string `json:"user_id"`
+		Username string `json:"username"`
+		IsActive *bool  `json:"is_active"`
+		Reason   string `json:"reason"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+
+	if req.TeamName == "" || req.UserID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name and user_id are required")
+		return
+	}
+
+	ctx := r.Context()
+
+	var (
+		member       models.User
+		previousTeam string
+	)
+
+	err := h.Storage.WithTx(ctx, func(st *storage.Storage) error {
+		_, err := st.TeamStorage.GetTeamByName(ctx, req.TeamName)
+		if errors.Is(err, storageErrors.ErrTeamNotFound) {
+			return newAPIError(http.StatusNotFound, "NOT_FOUND", "team not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		user, err := st.UserStorage.GetUserByID(ctx, req.UserID)
+
+		switch {
+		case errors.Is(err, storageErrors.ErrUserNotFound):
+			if req.Username == "" {
+				return newAPIError(http.StatusBadRequest, "BAD_REQUEST", "username is required for a new user")
+			}
+			if err := st.UserStorage.CreateUser(ctx, req.UserID, req.Username, req.TeamName); err != nil {
+				return err
+			}
+			user = models.User{UserID: req.UserID, Username: req.Username, TeamName: req.TeamName, IsActive: true}
+
+		case err == nil:
+			previousTeam = user.TeamName
+			if user.TeamName != req.TeamName {
+				if err := st.UserStorage.SetUserTeam(ctx, req.UserID, req.TeamName); err != nil {
+					return err
+				}
+			}
+
+		default:
+			return err
+		}
+
+		if req.IsActive != nil && *req.IsActive != user.IsActive {
+			if err := st.UserStorage.SetUserActiveStatus(ctx, req.UserID, *req.IsActive); err != nil {
+				return err
+			}
+
+			eventType := models.EventDeactivate
+			if *req.IsActive {
+				eventType = models.EventActivate
+			}
+
+			if err := recordEvent(ctx, st, r, models.AssignmentEvent{
+				EventType: eventType,
+				UserID:    req.UserID,
+				Reason:    req.Reason,
+			}); err != nil {
+				return err
+			}
+		}
+
+		member, err = st.UserStorage.GetUserByID(ctx, req.UserID)
+		return err
+	})
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	resp := map[string]any{
+		"team_name": req.TeamName,
+		"member":    member,
+	}
+	if previousTeam != "" {
+		resp["previous_team"] = previousTeam
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// RemoveTeamMember deletes a user that never took part in a pull request.
+// Users referenced by pull requests have to be deactivated or moved to
+// another team instead, so that history stays intact.
+func (h *Handler) RemoveTeamMember(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName string `json:"team_name"`
+		UserID   string `json:"user_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+
+	if req.TeamName == "" || req.UserID == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name and user_id are required")
+		return
+	}
+
+	ctx := r.Context()
+
+	err := h.Storage.WithTx(ctx, func(st *storage.Storage) error {
+		user, err := st.UserStorage.GetUserByID(ctx, req.UserID)
+		if errors.Is(err, storageErrors.ErrUserNotFound) {
+			return newAPIError(http.StatusNotFound, "NOT_FOUND", "user not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		if user.TeamName != req.TeamName {
+			return newAPIError(http.StatusNotFound, "NOT_FOUND", "user is not a member of this team")
+		}
+
+		count, err := st.PullRequestStorage.CountPullRequestsByUser(ctx, req.UserID)
+		if err != nil {
+			return err
+		}
+
+		if count > 0 {
+			referenced := newAPIError(http.StatusConflict, "MEMBER_REFERENCED",
+				"user authored or reviews pull requests; deactivate them or move them with /team/addMember")
+			referenced.details = map[string]any{"pull_requests": count}
+			return referenced
+		}
+
+		return st.UserStorage.DeleteUser(ctx, req.UserID)
+	})
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"team_name": req.TeamName,
+		"user_id":   req.UserID,
+	})
+}
+
+func (h *Handler) RenameTeam(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName    string `json:"team_name"`
+		NewTeamName string `json:"new_team_name"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid json")
+		return
+	}
+
+	if req.TeamName == "" || req.NewTeamName == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name and new_team_name are required")
+		return
+	}
+
+	if req.TeamName == req.NewTeamName {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "new_team_name must differ from team_name")
+		return
+	}
+
+	ctx := r.Context()
+
+	var team models.Team
+
+	err := h.Storage.WithTx(ctx, func(st *storage.Storage) error {
+		_, err := st.TeamStorage.GetTeamByName(ctx, req.TeamName)
+		if errors.Is(err, storageErrors.ErrTeamNotFound) {
+			return newAPIError(http.StatusNotFound, "NOT_FOUND", "team not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = st.TeamStorage.GetTeamByName(ctx, req.NewTeamName)
+		if err == nil {
+			return newAPIError(http.StatusBadRequest, "TEAM_EXISTS", "new_team_name already exists")
+		}
+		if !errors.Is(err, storageErrors.ErrTeamNotFound) {
+			return err
+		}
+
+		if err := st.TeamStorage.RenameTeam(ctx, req.TeamName, req.NewTeamName); err != nil {
+			return err
+		}
+
+		team, err = st.TeamStorage.GetTeamByName(ctx, req.NewTeamName)
+		return err
+	})
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	if team.Members == nil {
+		team.Members = []models.TeamMember{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"team": team})
+}
+
+// DeleteTeam removes an empty team. Teams with members, or listed as a
+// fallback by other teams, are rejected with what still references them.
+func (h *Handler) DeleteTeam(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "team_name is required")
+		return
+	}
+
+	ctx := r.Context()
+
+	err := h.Storage.WithTx(ctx, func(st *storage.Storage) error {
+		team, err := st.TeamStorage.GetTeamByName(ctx, teamName)
+		if errors.Is(err, storageErrors.ErrTeamNotFound) {
+			return newAPIError(http.StatusNotFound, "NOT_FOUND", "team not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(team.Members) > 0 {
+			notEmpty := newAPIError(http.StatusConflict, "TEAM_NOT_EMPTY", "move or remove the team's members first")
+			notEmpty.details = map[string]any{"members": len(team.Members)}
+			return notEmpty
+		}
+
+		dependents, err := st.TeamStorage.GetDependentTeams(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		if len(dependents) > 0 {
+			referenced := newAPIError(http.StatusConflict, "TEAM_REFERENCED", "team is a fallback of other teams")
+			referenced.details = map[string]any{"fallback_of": dependents}
+			return referenced
+		}
+
+		return st.TeamStorage.DeleteTeam(ctx, teamName)
+	})
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"team_name": teamName})
+}
@@ -0,0 +1,70 @@
+// Package mergepolicy decides whether a pull request may be merged under its
+// team's merge policy.
+package mergepolicy
+
+import "github.com/pacahar/pr-reviewer-assignment/internal/models"
+
+const (
+	RuleMinApprovals          string = "min_approvals"
+	RuleNoChangesRequested    string = "no_changes_requested"
+	RuleAuthorNotSoleApprover string = "author_not_sole_approver"
+	RuleAllReviewersResponded string = "all_reviewers_responded"
+)
+
+// Violation is a single unmet rule.
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Rule checks one merge condition and returns nil when it holds.
+type Rule interface {
+	Check(pr models.PullRequest) *Violation
+}
+
+// Rules returns the rules enabled by policy.
+func Rules(policy models.MergePolicy) []Rule {
+	var rules []Rule
+
+	if policy.MinApprovals > 0 {
+		rules = append(rules, MinApprovals{Count: policy.MinApprovals})
+	}
+	if policy.BlockOnChangesRequested {
+		rules = append(rules, NoChangesRequested{})
+	}
+	if policy.ForbidAuthorSoleApprover {
+		rules = append(rules, AuthorNotSoleApprover{})
+	}
+	if policy.RequireAllReviewers {
+		rules = append(rules, AllReviewersResponded{})
+	}
+
+	return rules
+}
+
+// Evaluate checks every rule of policy against pr and returns all violations,
+// not just the first one.
+func Evaluate(policy models.MergePolicy, pr models.PullRequest) []Violation {
+	violations := []Violation{}
+
+	for _, rule := range Rules(policy) {
+		if v := rule.Check(pr); v != nil {
+			violations = append(violations, *v)
+		}
+	}
+
+	return violations
+}
+
+// countStates counts reviewers in state. The author's own verdict is left out;
+// it only matters to AuthorNotSoleApprover.
+func countStates(pr models.PullRequest, state models.ReviewState) int {
+	n := 0
+	for _, review := range pr.Reviews {
+		if review.ReviewerID != pr.AuthorID && review.State == state {
+			n++
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,97 @@
+package mergepolicy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+)
+
+// MinApprovals requires at least Count approving reviews.
+type MinApprovals struct {
+	Count int
+}
+
+func (r MinApprovals) Check(pr models.PullRequest) *Violation {
+	approvals := countStates(pr, models.ReviewApproved)
+	if approvals >= r.Count {
+		return nil
+	}
+
+	return &Violation{
+		Rule:    RuleMinApprovals,
+		Message: fmt.Sprintf("pull request has %d of %d required approvals", approvals, r.Count),
+	}
+}
+
+// NoChangesRequested blocks while any reviewer's latest verdict asks for
+// changes.
+type NoChangesRequested struct{}
+
+func (r NoChangesRequested) Check(pr models.PullRequest) *Violation {
+	var blocking []string
+	for _, review := range pr.Reviews {
+		if review.State == models.ReviewChangesRequested {
+			blocking = append(blocking, review.ReviewerID)
+		}
+	}
+
+	if len(blocking) == 0 {
+		return nil
+	}
+
+	return &Violation{
+		Rule:    RuleNoChangesRequested,
+		Message: "changes requested by " + strings.Join(blocking, ", "),
+	}
+}
+
+// AuthorNotSoleApprover blocks when the author's own approval is the only
+// one.
+type AuthorNotSoleApprover struct{}
+
+func (r AuthorNotSoleApprover) Check(pr models.PullRequest) *Violation {
+	authorApproved := false
+	others := 0
+
+	for _, review := range pr.Reviews {
+		if review.State != models.ReviewApproved {
+			continue
+		}
+		if review.ReviewerID == pr.AuthorID {
+			authorApproved = true
+		} else {
+			others++
+		}
+	}
+
+	if !authorApproved || others > 0 {
+		return nil
+	}
+
+	return &Violation{
+		Rule:    RuleAuthorNotSoleApprover,
+		Message: "the author is the only approver",
+	}
+}
+
+// AllReviewersResponded requires a verdict from every assigned reviewer.
+type AllReviewersResponded struct{}
+
+func (r AllReviewersResponded) Check(pr models.PullRequest) *Violation {
+	var pending []string
+	for _, review := range pr.Reviews {
+		if review.State == models.ReviewPending {
+			pending = append(pending, review.ReviewerID)
+		}
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	return &Violation{
+		Rule:    RuleAllReviewersResponded,
+		Message: "waiting for " + strings.Join(pending, ", "),
+	}
+}
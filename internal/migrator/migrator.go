@@ -0,0 +1,417 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// lockID is the Postgres advisory lock key held while migrating, so that
+// parallel migrate runs wait for each other instead of colliding.
+const lockID int64 = 4721938562
+
+var ErrSchemaNewer = errors.New("database schema is newer than this binary")
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New reads migrations from the root of fsys. Every version needs both an up
+// and a down file.
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	const op = "migrator.New"
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	byVersion := map[int64]*Migration{}
+
+	for _, e := range entries {
+		match := fileName.FindStringSubmatch(e.Name())
+		if e.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", op, e.Name(), err)
+		}
+
+		data, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("%s: version %d has files named %q and %q", op, version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("%s: version %d needs both up and down files", op, m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Latest returns the highest version known to the binary.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies every pending migration. It refuses to touch a database that
+// already has migrations applied which this binary does not know about; the
+// check and the migrations run under one lock, so a newer binary migrating
+// concurrently cannot slip in between.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	const op = "migrator.Up"
+
+	var done []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if versions := sortedVersions(applied); len(versions) > 0 {
+			if err := m.checkVersion(versions[len(versions)-1]); err != nil {
+				return err
+			}
+		}
+
+		done, err = m.migrateTo(ctx, conn, applied, m.Latest())
+		return err
+	})
+	if err != nil {
+		return done, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return done, nil
+}
+
+// Down rolls back the last n applied migrations.
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	const op = "migrator.Down"
+
+	var done []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		versions := sortedVersions(applied)
+		for i := len(versions) - 1; i >= 0 && len(done) < n; i-- {
+			mig, err := m.find(versions[i])
+			if err != nil {
+				return err
+			}
+
+			if err := m.apply(ctx, conn, mig, false); err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return done, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return done, nil
+}
+
+// Goto migrates up or down until exactly the migrations up to version are
+// applied.
+func (m *Migrator) Goto(ctx context.Context, version int64) ([]Migration, error) {
+	const op = "migrator.Goto"
+
+	if version != 0 {
+		if _, err := m.find(version); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	var done []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		done, err = m.migrateTo(ctx, conn, applied, version)
+		return err
+	})
+	if err != nil {
+		return done, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return done, nil
+}
+
+// migrateTo rolls back applied migrations above version and applies the
+// missing ones up to it. The caller holds the lock.
+func (m *Migrator) migrateTo(ctx context.Context, conn *sql.Conn, applied map[int64]time.Time, version int64) ([]Migration, error) {
+	var done []Migration
+
+	versions := sortedVersions(applied)
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i] <= version {
+			break
+		}
+
+		mig, err := m.find(versions[i])
+		if err != nil {
+			return done, err
+		}
+
+		if err := m.apply(ctx, conn, mig, false); err != nil {
+			return done, err
+		}
+		done = append(done, mig)
+	}
+
+	for _, mig := range m.migrations {
+		if mig.Version > version {
+			break
+		}
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+
+		if err := m.apply(ctx, conn, mig, true); err != nil {
+			return done, err
+		}
+		done = append(done, mig)
+	}
+
+	return done, nil
+}
+
+// Status lists every known migration and whether it is applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	const op = "migrator.Status"
+
+	var result []Status
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			s := Status{Migration: mig}
+			if at, ok := applied[mig.Version]; ok {
+				s.Applied = true
+				s.AppliedAt = &at
+			}
+			result = append(result, s)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result, nil
+}
+
+// Check returns ErrSchemaNewer when the database has migrations applied which
+// this binary does not know about. It only reads, so it does not need the
+// lock and works on a database that was never migrated.
+func (m *Migrator) Check(ctx context.Context) error {
+	const op = "migrator.Check"
+
+	var exists bool
+	if err := m.db.QueryRowContext(ctx, `
+		SELECT to_regclass('schema_migrations') IS NOT NULL;`,
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !exists {
+		return nil
+	}
+
+	var version int64
+	if err := m.db.QueryRowContext(ctx, `
+		SELECT COALESCE(MAX(version), 0)
+		FROM schema_migrations;`,
+	).Scan(&version); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := m.checkVersion(version); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) checkVersion(version int64) error {
+	if version > m.Latest() {
+		return fmt.Errorf("%w: database at %d, binary at %d", ErrSchemaNewer, version, m.Latest())
+	}
+
+	return nil
+}
+
+func (m *Migrator) find(version int64) (Migration, error) {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return mig, nil
+		}
+	}
+
+	return Migration{}, fmt.Errorf("unknown migration version %d", version)
+}
+
+// apply runs one migration and records it in the same transaction.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mig Migration, up bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	script, direction := mig.Down, "down"
+	if up {
+		script, direction = mig.Up, "up"
+	}
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("%d_%s.%s.sql: %w", mig.Version, mig.Name, direction, err)
+	}
+
+	if up {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO schema_migrations (version)
+			VALUES ($1);`,
+			mig.Version,
+		)
+	} else {
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM schema_migrations
+			WHERE version = $1;`,
+			mig.Version,
+		)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// withLock runs fn on a single connection holding the advisory lock.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1);`, lockID); err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1);`, lockID)
+
+	if _, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+		    version BIGINT PRIMARY KEY,
+		    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		);`,
+	); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, `
+		SELECT version, applied_at
+		FROM schema_migrations;`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := map[int64]time.Time{}
+
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		result[version] = appliedAt
+	}
+
+	return result, rows.Err()
+}
+
+func sortedVersions(applied map[int64]time.Time) []int64 {
+	versions := make([]int64, 0, len(applied))
+	for v := range applied {
+		versions = append(versions, v)
+	}
+
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+
+	return versions
+}
@@ -0,0 +1,267 @@
+package migrator_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/migrator"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage/storagetest"
+)
+
+// migrationFS returns versions 1..n, each creating table tN.
+func migrationFS(n int) fstest.MapFS {
+	fsys := fstest.MapFS{}
+
+	for v := 1; v <= n; v++ {
+		fsys[fmt.Sprintf("%06d_t%d.up.sql", v, v)] = &fstest.MapFile{
+			Data: []byte(fmt.Sprintf("CREATE TABLE t%d (id INT);", v)),
+		}
+		fsys[fmt.Sprintf("%06d_t%d.down.sql", v, v)] = &fstest.MapFile{
+			Data: []byte(fmt.Sprintf("DROP TABLE t%d;", v)),
+		}
+	}
+
+	return fsys
+}
+
+// newDB connects to the database in STORAGETEST_POSTGRES_DSN with a fresh
+// schema first on the search path, so the migrations under test do not touch
+// the conformance suite's tables. The test is skipped when it is unset.
+func newDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv(storagetest.PostgresDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", storagetest.PostgresDSNEnv)
+	}
+
+	schema := fmt.Sprintf("migrator_test_%d", time.Now().UnixNano())
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open postgres: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema + `;`); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE;`); err != nil {
+			t.Errorf("drop schema: %v", err)
+		}
+	})
+
+	db, err := sql.Open("postgres", withSearchPath(dsn, schema))
+	if err != nil {
+		t.Fatalf("open postgres: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+// withSearchPath adds a search_path run-time parameter to a URL or key=value
+// DSN.
+func withSearchPath(dsn, schema string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err == nil {
+			q := u.Query()
+			q.Set("search_path", schema)
+			u.RawQuery = q.Encode()
+			return u.String()
+		}
+	}
+
+	return dsn + " search_path=" + schema
+}
+
+func newMigrator(t *testing.T, db *sql.DB, n int) *migrator.Migrator {
+	t.Helper()
+
+	m, err := migrator.New(db, migrationFS(n))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	return m
+}
+
+func versions(done []migrator.Migration) []int64 {
+	result := []int64{}
+	for _, mig := range done {
+		result = append(result, mig.Version)
+	}
+
+	return result
+}
+
+func mustVersions(t *testing.T, what string, done []migrator.Migration, err error, want ...int64) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("%s: %v", what, err)
+	}
+	if got := versions(done); !reflect.DeepEqual(got, append([]int64{}, want...)) {
+		t.Fatalf("%s: got %v, want %v", what, got, want)
+	}
+}
+
+// assertApplied checks both schema_migrations, through Status, and the tables
+// the migrations created.
+func assertApplied(t *testing.T, db *sql.DB, m *migrator.Migrator, want ...int64) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+
+	applied := []int64{}
+	for _, s := range statuses {
+		if s.Applied != (s.AppliedAt != nil) {
+			t.Errorf("status %d: applied %v with applied_at %v", s.Version, s.Applied, s.AppliedAt)
+		}
+		if s.Applied {
+			applied = append(applied, s.Version)
+		}
+
+		var exists bool
+		if err := db.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL;`, fmt.Sprintf("t%d", s.Version)).Scan(&exists); err != nil {
+			t.Fatalf("to_regclass: %v", err)
+		}
+		if exists != s.Applied {
+			t.Errorf("table t%d exists = %v, but migration applied = %v", s.Version, exists, s.Applied)
+		}
+	}
+
+	if !reflect.DeepEqual(applied, append([]int64{}, want...)) {
+		t.Fatalf("applied versions = %v, want %v", applied, want)
+	}
+}
+
+func TestUpAndDown(t *testing.T) {
+	db := newDB(t)
+	m := newMigrator(t, db, 3)
+	ctx := context.Background()
+
+	assertApplied(t, db, m)
+
+	done, err := m.Up(ctx)
+	mustVersions(t, "Up", done, err, 1, 2, 3)
+	assertApplied(t, db, m, 1, 2, 3)
+
+	done, err = m.Up(ctx)
+	mustVersions(t, "second Up", done, err)
+
+	done, err = m.Down(ctx, 2)
+	mustVersions(t, "Down 2", done, err, 3, 2)
+	assertApplied(t, db, m, 1)
+
+	done, err = m.Down(ctx, 5)
+	mustVersions(t, "Down past the first migration", done, err, 1)
+	assertApplied(t, db, m)
+}
+
+func TestGoto(t *testing.T) {
+	db := newDB(t)
+	m := newMigrator(t, db, 3)
+	ctx := context.Background()
+
+	done, err := m.Goto(ctx, 2)
+	mustVersions(t, "Goto 2", done, err, 1, 2)
+	assertApplied(t, db, m, 1, 2)
+
+	done, err = m.Goto(ctx, 3)
+	mustVersions(t, "Goto 3", done, err, 3)
+	assertApplied(t, db, m, 1, 2, 3)
+
+	done, err = m.Goto(ctx, 1)
+	mustVersions(t, "Goto 1", done, err, 3, 2)
+	assertApplied(t, db, m, 1)
+
+	done, err = m.Goto(ctx, 0)
+	mustVersions(t, "Goto 0", done, err, 1)
+	assertApplied(t, db, m)
+}
+
+func TestGotoUnknownVersion(t *testing.T) {
+	db := newDB(t)
+	m := newMigrator(t, db, 3)
+
+	done, err := m.Goto(context.Background(), 4)
+	if err == nil || !strings.Contains(err.Error(), "unknown migration version 4") {
+		t.Fatalf("Goto 4: got %v, want unknown version error", err)
+	}
+	if len(done) != 0 {
+		t.Fatalf("Goto 4 ran %v", versions(done))
+	}
+
+	assertApplied(t, db, m)
+}
+
+func TestUpRefusesNewerSchema(t *testing.T) {
+	db := newDB(t)
+	ctx := context.Background()
+
+	done, err := newMigrator(t, db, 3).Up(ctx)
+	mustVersions(t, "Up", done, err, 1, 2, 3)
+
+	older := newMigrator(t, db, 2)
+
+	if _, err := older.Up(ctx); !errors.Is(err, migrator.ErrSchemaNewer) {
+		t.Fatalf("Up from an older binary: got %v, want ErrSchemaNewer", err)
+	}
+
+	if err := older.Check(ctx); !errors.Is(err, migrator.ErrSchemaNewer) {
+		t.Fatalf("Check from an older binary: got %v, want ErrSchemaNewer", err)
+	}
+
+	assertApplied(t, db, newMigrator(t, db, 3), 1, 2, 3)
+}
+
+func TestCheck(t *testing.T) {
+	db := newDB(t)
+	m := newMigrator(t, db, 3)
+	ctx := context.Background()
+
+	if err := m.Check(ctx); err != nil {
+		t.Fatalf("Check on an empty database: %v", err)
+	}
+
+	var exists bool
+	if err := db.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL;`).Scan(&exists); err != nil {
+		t.Fatalf("to_regclass: %v", err)
+	}
+	if exists {
+		t.Fatal("Check created schema_migrations")
+	}
+
+	done, err := m.Goto(ctx, 2)
+	mustVersions(t, "Goto 2", done, err, 1, 2)
+
+	if err := m.Check(ctx); err != nil {
+		t.Fatalf("Check behind the binary: %v", err)
+	}
+}
+
+func TestNewRejectsIncompleteMigrations(t *testing.T) {
+	fsys := migrationFS(2)
+	delete(fsys, "000002_t2.down.sql")
+
+	if _, err := migrator.New(nil, fsys); err == nil {
+		t.Fatal("New without a down file: got nil error")
+	}
+}
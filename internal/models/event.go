@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type EventType string
+
+const (
+	EventAssign     EventType = "ASSIGN"
+	EventReassign   EventType = "REASSIGN"
+	EventMerge      EventType = "MERGE"
+	EventActivate   EventType = "ACTIVATE"
+	EventDeactivate EventType = "DEACTIVATE"
+)
+
+// AssignmentEvent is an entry of the append-only audit log. UserID is the
+// user the event is about; for REASSIGN it is the replaced reviewer and
+// ReplacementID the new one.
+type AssignmentEvent struct {
+	EventID       int64     `json:"event_id"`
+	EventType     EventType `json:"event_type"`
+	PullRequestID string    `json:"pull_request_id,omitempty"`
+	UserID        string    `json:"user_id,omitempty"`
+	ReplacementID string    `json:"replacement_id,omitempty"`
+	Actor         string    `json:"actor,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
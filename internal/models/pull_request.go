@@ -2,19 +2,87 @@ package models
 
 import "time"
 
+type PullRequestStatus string
+
+const (
+	StatusDraft  PullRequestStatus = "DRAFT"
+	StatusOpen   PullRequestStatus = "OPEN"
+	StatusClosed PullRequestStatus = "CLOSED"
+	StatusMerged PullRequestStatus = "MERGED"
+)
+
+// transitions lists the statuses each status may move to. MERGED is final.
+var transitions = map[PullRequestStatus][]PullRequestStatus{
+	StatusDraft:  {StatusOpen, StatusClosed},
+	StatusOpen:   {StatusClosed, StatusMerged},
+	StatusClosed: {StatusOpen},
+}
+
+func (s PullRequestStatus) CanTransitionTo(next PullRequestStatus) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
 type PullRequest struct {
-	PullRequestID     string     `json:"pull_request_id"`
-	PullRequestName   string     `json:"pull_request_name"`
-	AuthorID          string     `json:"author_id"`
-	Status            string     `json:"status"` // "OPEN", "MERGED"
-	AssignedReviewers []string   `json:"assigned_reviewers"`
-	CreatedAt         *time.Time `json:"createdAt,omitempty"`
-	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+	PullRequestID     string            `json:"pull_request_id"`
+	PullRequestName   string            `json:"pull_request_name"`
+	AuthorID          string            `json:"author_id"`
+	Status            PullRequestStatus `json:"status"`
+	AssignedReviewers []string          `json:"assigned_reviewers"`
+	Reviews           []Review          `json:"reviews,omitempty"`
+	CreatedAt         *time.Time        `json:"createdAt,omitempty"`
+	MergedAt          *time.Time        `json:"mergedAt,omitempty"`
 }
 
 type PullRequestShort struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
-	Status          string `json:"status"`
+	PullRequestID   string            `json:"pull_request_id"`
+	PullRequestName string            `json:"pull_request_name"`
+	AuthorID        string            `json:"author_id"`
+	Status          PullRequestStatus `json:"status"`
+	ReviewState     ReviewState       `json:"review_state,omitempty"`
+	CreatedAt       time.Time         `json:"createdAt"`
+}
+
+// PullRequestFilter selects pull requests. Zero fields do not filter;
+// CreatedTo is exclusive. TeamName matches the author's team and
+// NameContains is a case-insensitive substring of the name.
+type PullRequestFilter struct {
+	Statuses     []PullRequestStatus
+	AuthorID     string
+	TeamName     string
+	ReviewerID   string
+	NameContains string
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+}
+
+type PullRequestSort string
+
+const (
+	SortByCreatedAt PullRequestSort = "created_at"
+	SortByName      PullRequestSort = "name"
+)
+
+// PullRequestCursor is the position of the last pull request of a page. Only
+// the field of the sort key is used besides PullRequestID.
+type PullRequestCursor struct {
+	CreatedAt       time.Time `json:"created_at"`
+	PullRequestName string    `json:"pull_request_name,omitempty"`
+	PullRequestID   string    `json:"pull_request_id"`
+}
+
+// PullRequestQuery is a page of pull requests ordered by Sort, then id, both
+// descending when Desc is set. The zero Sort orders by creation time. Limit 0
+// returns every matching pull request after After.
+type PullRequestQuery struct {
+	PullRequestFilter
+	Sort  PullRequestSort
+	Desc  bool
+	After *PullRequestCursor
+	Limit int
 }
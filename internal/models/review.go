@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+type ReviewState string
+
+const (
+	ReviewPending          ReviewState = "PENDING"
+	ReviewApproved         ReviewState = "APPROVED"
+	ReviewChangesRequested ReviewState = "CHANGES_REQUESTED"
+	ReviewCommented        ReviewState = "COMMENTED"
+)
+
+// IsVerdict reports whether s is a state a reviewer may submit.
+func (s ReviewState) IsVerdict() bool {
+	switch s {
+	case ReviewApproved, ReviewChangesRequested, ReviewCommented:
+		return true
+	}
+
+	return false
+}
+
+// Review is one reviewer's verdict. The author may review their own pull
+// request too; such a review has no AssignmentSeed, which is otherwise the
+// seed of the randomized draw that picked the reviewer, if any.
+type Review struct {
+	ReviewerID     string      `json:"reviewer_id"`
+	State          ReviewState `json:"state"`
+	AssignmentSeed *int64      `json:"assignment_seed,omitempty"`
+	SubmittedAt    *time.Time  `json:"submittedAt,omitempty"`
+}
+
+// ReviewerReplacement swaps OldReviewerID for NewReviewerID on a pull
+// request. Seed is the seed of the draw that picked the new reviewer.
+type ReviewerReplacement struct {
+	PullRequestID string
+	OldReviewerID string
+	NewReviewerID string
+	Seed          *int64
+}
+
+// ReviewAssignment is one reviewer of a pull request together with the team
+// the reviewer belongs to.
+type ReviewAssignment struct {
+	PullRequestID string
+	AuthorID      string
+	ReviewerID    string
+	ReviewerTeam  string
+}
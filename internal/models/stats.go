@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// StatsFilter narrows statistics to one team and to pull requests and
+// events created in [From, To). Empty fields do not filter.
+type StatsFilter struct {
+	TeamName string
+	From     *time.Time
+	To       *time.Time
+}
+
+// ReviewCounts are review assignment counters shared by user and team
+// statistics. Assignments count the current reviewers of pull requests, so a
+// reviewer that was replaced only shows up in ReassignedOut.
+type ReviewCounts struct {
+	TotalAssignments      int      `json:"total_assignments"`
+	OpenReviews           int      `json:"open_reviews"`
+	MergedReviews         int      `json:"merged_reviews"`
+	ReassignedIn          int      `json:"reassigned_in"`
+	ReassignedOut         int      `json:"reassigned_out"`
+	AvgTimeToMergeSeconds *float64 `json:"avg_time_to_merge_seconds"`
+}
+
+type UserStats struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive bool   `json:"is_active"`
+	ReviewCounts
+}
+
+type TeamStats struct {
+	TeamName string `json:"team_name"`
+	Members  int    `json:"members"`
+	ReviewCounts
+}
+
+// ReviewLoad is the number of reviews assigned to an active user on pull
+// requests created within a window.
+type ReviewLoad struct {
+	UserID     string `json:"user_id"`
+	Username   string `json:"username"`
+	Reviews    int    `json:"reviews"`
+	Overloaded bool   `json:"overloaded"`
+}
+
+// LoadDistribution summarizes review load across a team's active members.
+// StdDev is the population standard deviation; Gini is 0 for a perfectly
+// even load and approaches 1 as one member takes every review. Users are
+// ordered from the most to the least loaded.
+type LoadDistribution struct {
+	Members      int          `json:"members"`
+	TotalReviews int          `json:"total_reviews"`
+	Min          int          `json:"min"`
+	Max          int          `json:"max"`
+	Mean         float64      `json:"mean"`
+	StdDev       float64      `json:"stddev"`
+	Gini         float64      `json:"gini"`
+	Users        []ReviewLoad `json:"users"`
+}
@@ -1,8 +1,11 @@
 package models
 
 type Team struct {
-	TeamName string       `json:"team_name"`
-	Members  []TeamMember `json:"members"`
+	TeamName          string       `json:"team_name"`
+	RequiredReviewers int          `json:"required_reviewers"`
+	FallbackTeams     []string     `json:"fallback_teams"`
+	MergePolicy       MergePolicy  `json:"merge_policy"`
+	Members           []TeamMember `json:"members"`
 }
 
 type TeamMember struct {
@@ -10,3 +13,19 @@ type TeamMember struct {
 	Username string `json:"username"`
 	IsActive bool   `json:"is_active"`
 }
+
+// MergePolicy lists the conditions a team's pull requests must meet before
+// they can be merged. The zero value allows every merge.
+type MergePolicy struct {
+	MinApprovals             int  `json:"min_approvals"`
+	BlockOnChangesRequested  bool `json:"block_on_changes_requested"`
+	ForbidAuthorSoleApprover bool `json:"forbid_author_sole_approver"`
+	RequireAllReviewers      bool `json:"require_all_reviewers"`
+}
+
+type TeamSummary struct {
+	TeamName          string `json:"team_name"`
+	RequiredReviewers int    `json:"required_reviewers"`
+	Members           int    `json:"members"`
+	ActiveMembers     int    `json:"active_members"`
+}
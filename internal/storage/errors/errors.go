@@ -6,4 +6,6 @@ var (
 	ErrUserNotFound = errors.New("user not found")
 	ErrTeamNotFound = errors.New("team not found")
 	ErrPRNotFound   = errors.New("pull request not found")
+
+	ErrReviewerNotAssigned = errors.New("reviewer not assigned")
 )
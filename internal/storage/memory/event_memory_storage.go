@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+)
+
+type EventMemoryStorage struct {
+	s *session
+}
+
+func (es *EventMemoryStorage) AddEvent(ctx context.Context, event models.AssignmentEvent) error {
+	return es.s.do(func(st *state) error {
+		event.EventID = int64(len(st.events)) + 1
+		st.events = append(st.events, event)
+
+		return nil
+	})
+}
+
+func (es *EventMemoryStorage) AddEvents(ctx context.Context, events []models.AssignmentEvent) error {
+	return es.s.do(func(st *state) error {
+		for _, e := range events {
+			e.EventID = int64(len(st.events)) + 1
+			st.events = append(st.events, e)
+		}
+
+		return nil
+	})
+}
+
+func (es *EventMemoryStorage) GetEventsByPR(ctx context.Context, prID string) ([]models.AssignmentEvent, error) {
+	return es.filter(func(e models.AssignmentEvent) bool {
+		return e.PullRequestID == prID
+	})
+}
+
+func (es *EventMemoryStorage) GetEventsByUser(ctx context.Context, userID string) ([]models.AssignmentEvent, error) {
+	return es.filter(func(e models.AssignmentEvent) bool {
+		return e.UserID == userID || e.ReplacementID == userID
+	})
+}
+
+func (es *EventMemoryStorage) filter(keep func(e models.AssignmentEvent) bool) ([]models.AssignmentEvent, error) {
+	result := []models.AssignmentEvent{}
+
+	err := es.s.do(func(st *state) error {
+		for _, e := range st.events {
+			if keep(e) {
+				result = append(result, e)
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+)
+
+// Mirror the constraint violations the SQL backends report.
+var (
+	errDuplicateKey = errors.New("duplicate key")
+	errForeignKey   = errors.New("foreign key violation")
+)
+
+type team struct {
+	requiredReviewers int
+	fallbackTeams     []string
+	mergePolicy       models.MergePolicy
+}
+
+type pullRequest struct {
+	id        string
+	name      string
+	authorID  string
+	status    models.PullRequestStatus
+	createdAt time.Time
+	mergedAt  *time.Time
+
+	authorReview     models.ReviewState
+	authorReviewedAt *time.Time
+}
+
+type reviewer struct {
+	userID     string
+	seed       *int64
+	state      models.ReviewState
+	reviewedAt *time.Time
+}
+
+// state holds every table of the in-memory database.
+type state struct {
+	teams     map[string]team
+	rotations map[string]int64
+	users     map[string]models.User
+	prs       map[string]pullRequest
+	reviewers map[string][]reviewer
+	events    []models.AssignmentEvent
+}
+
+func newState() *state {
+	return &state{
+		teams:     map[string]team{},
+		rotations: map[string]int64{},
+		users:     map[string]models.User{},
+		prs:       map[string]pullRequest{},
+		reviewers: map[string][]reviewer{},
+	}
+}
+
+func (s *state) clone() *state {
+	c := newState()
+
+	for k, v := range s.teams {
+		v.fallbackTeams = append([]string(nil), v.fallbackTeams...)
+		c.teams[k] = v
+	}
+	for k, v := range s.rotations {
+		c.rotations[k] = v
+	}
+	for k, v := range s.users {
+		c.users[k] = v
+	}
+	for k, v := range s.prs {
+		c.prs[k] = v
+	}
+	for k, v := range s.reviewers {
+		c.reviewers[k] = append([]reviewer(nil), v...)
+	}
+	c.events = append([]models.AssignmentEvent(nil), s.events...)
+
+	return c
+}
+
+type db struct {
+	mu    sync.Mutex
+	state *state
+}
+
+// session gives storages access to the state: directly under the db lock,
+// or to the private copy of a running transaction.
+type session struct {
+	db *db
+	tx *state
+}
+
+func (s *session) do(fn func(st *state) error) error {
+	if s.tx != nil {
+		return fn(s.tx)
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	return fn(s.db.state)
+}
+
+func NewMemoryStorage() *storage.Storage {
+	d := &db{state: newState()}
+
+	return newStorage(&session{db: d}, &MemoryTransactor{db: d})
+}
+
+func newStorage(s *session, transactor storage.Transactor) *storage.Storage {
+	return &storage.Storage{
+		UserStorage:        &UserMemoryStorage{s: s},
+		TeamStorage:        &TeamMemoryStorage{s: s},
+		PullRequestStorage: &PullRequestMemoryStorage{s: s},
+		EventStorage:       &EventMemoryStorage{s: s},
+		StatsStorage:       &StatsMemoryStorage{s: s},
+		Transactor:         transactor,
+	}
+}
+
+// MemoryTransactor runs transactions one at a time against a copy of the
+// state. The copy replaces the state on success and is dropped otherwise.
+type MemoryTransactor struct {
+	db *db
+}
+
+func (t *MemoryTransactor) WithTx(ctx context.Context, fn func(st *storage.Storage) error) error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	tx := t.db.state.clone()
+
+	txStorage := newStorage(&session{db: t.db, tx: tx}, nil)
+	txStorage.Transactor = &txTransactor{st: txStorage}
+
+	if err := fn(txStorage); err != nil {
+		return err
+	}
+
+	t.db.state = tx
+
+	return nil
+}
+
+type txTransactor struct {
+	st *storage.Storage
+}
+
+func (t *txTransactor) WithTx(ctx context.Context, fn func(st *storage.Storage) error) error {
+	return fn(t.st)
+}
@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage/memory"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage/storagetest"
+)
+
+func TestMemoryStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) *storage.Storage {
+		return memory.NewMemoryStorage()
+	})
+}
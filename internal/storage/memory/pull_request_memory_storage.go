@@ -0,0 +1,505 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
+)
+
+type PullRequestMemoryStorage struct {
+	s *session
+}
+
+func (prs *PullRequestMemoryStorage) CreatePullRequest(ctx context.Context, prID, prName, authorID string, status models.PullRequestStatus) error {
+	const op = "storage.memory.CreatePullRequest"
+
+	return prs.s.do(func(st *state) error {
+		if _, ok := st.prs[prID]; ok {
+			return fmt.Errorf("%s: %w: pull request %q", op, errDuplicateKey, prID)
+		}
+		if _, ok := st.users[authorID]; !ok {
+			return fmt.Errorf("%s: %w: user %q", op, errForeignKey, authorID)
+		}
+
+		st.prs[prID] = pullRequest{
+			id:        prID,
+			name:      prName,
+			authorID:  authorID,
+			status:    status,
+			createdAt: time.Now().UTC(),
+		}
+
+		return nil
+	})
+}
+
+func (prs *PullRequestMemoryStorage) GetPullRequestByID(ctx context.Context, prID string) (models.PullRequest, error) {
+	var result models.PullRequest
+
+	err := prs.s.do(func(st *state) error {
+		pr, ok := st.prs[prID]
+		if !ok {
+			return storageErrors.ErrPRNotFound
+		}
+
+		createdAt := pr.createdAt
+		result = models.PullRequest{
+			PullRequestID:     pr.id,
+			PullRequestName:   pr.name,
+			AuthorID:          pr.authorID,
+			Status:            pr.status,
+			AssignedReviewers: reviewerIDs(st, prID),
+			Reviews:           reviews(st, prID),
+			CreatedAt:         &createdAt,
+		}
+
+		if pr.mergedAt != nil {
+			mergedAt := *pr.mergedAt
+			result.MergedAt = &mergedAt
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// LockPullRequest only checks existence: transactions already run one at a
+// time, which serializes every change to the pull request.
+func (prs *PullRequestMemoryStorage) LockPullRequest(ctx context.Context, prID string) error {
+	return prs.s.do(func(st *state) error {
+		if _, ok := st.prs[prID]; !ok {
+			return storageErrors.ErrPRNotFound
+		}
+
+		return nil
+	})
+}
+
+func (prs *PullRequestMemoryStorage) SetPullRequestStatus(ctx context.Context, prID string, status models.PullRequestStatus, now time.Time) error {
+	return prs.s.do(func(st *state) error {
+		pr, ok := st.prs[prID]
+		if !ok {
+			return nil
+		}
+
+		pr.status = status
+		pr.mergedAt = nil
+		if status == models.StatusMerged {
+			pr.mergedAt = &now
+		}
+		st.prs[prID] = pr
+
+		return nil
+	})
+}
+
+func (prs *PullRequestMemoryStorage) AddReviewer(ctx context.Context, prID, userID string, seed *int64) error {
+	const op = "storage.memory.AddReviewer"
+
+	return prs.s.do(func(st *state) error {
+		if _, ok := st.prs[prID]; !ok {
+			return fmt.Errorf("%s: %w: pull request %q", op, errForeignKey, prID)
+		}
+		if _, ok := st.users[userID]; !ok {
+			return fmt.Errorf("%s: %w: user %q", op, errForeignKey, userID)
+		}
+
+		for _, r := range st.reviewers[prID] {
+			if r.userID == userID {
+				return nil
+			}
+		}
+
+		st.reviewers[prID] = append(st.reviewers[prID], reviewer{
+			userID: userID,
+			seed:   seed,
+			state:  models.ReviewPending,
+		})
+
+		return nil
+	})
+}
+
+func (prs *PullRequestMemoryStorage) ReplaceReviewers(ctx context.Context, replacements []models.ReviewerReplacement) error {
+	const op = "storage.memory.ReplaceReviewers"
+
+	return prs.s.do(func(st *state) error {
+		for _, r := range replacements {
+			if _, ok := st.prs[r.PullRequestID]; !ok {
+				return fmt.Errorf("%s: %w: pull request %q", op, errForeignKey, r.PullRequestID)
+			}
+			if _, ok := st.users[r.NewReviewerID]; !ok {
+				return fmt.Errorf("%s: %w: user %q", op, errForeignKey, r.NewReviewerID)
+			}
+		}
+
+		for _, r := range replacements {
+			rows := st.reviewers[r.PullRequestID]
+
+			kept := make([]reviewer, 0, len(rows)+1)
+			assigned := false
+			for _, row := range rows {
+				if row.userID == r.OldReviewerID {
+					continue
+				}
+				assigned = assigned || row.userID == r.NewReviewerID
+				kept = append(kept, row)
+			}
+
+			if !assigned {
+				kept = append(kept, reviewer{
+					userID: r.NewReviewerID,
+					seed:   r.Seed,
+					state:  models.ReviewPending,
+				})
+			}
+
+			st.reviewers[r.PullRequestID] = kept
+		}
+
+		return nil
+	})
+}
+
+func (prs *PullRequestMemoryStorage) RemoveReviewer(ctx context.Context, prID, userID string) error {
+	return prs.s.do(func(st *state) error {
+		rows := st.reviewers[prID]
+		for i, r := range rows {
+			if r.userID == userID {
+				st.reviewers[prID] = append(rows[:i:i], rows[i+1:]...)
+				break
+			}
+		}
+
+		return nil
+	})
+}
+
+func (prs *PullRequestMemoryStorage) GetReviewersByPR(ctx context.Context, prID string) ([]string, error) {
+	var reviewers []string
+
+	err := prs.s.do(func(st *state) error {
+		reviewers = reviewerIDs(st, prID)
+		return nil
+	})
+
+	return reviewers, err
+}
+
+func (prs *PullRequestMemoryStorage) SetReviewState(ctx context.Context, prID, reviewerID string, reviewState models.ReviewState, now time.Time) error {
+	return prs.s.do(func(st *state) error {
+		rows := st.reviewers[prID]
+		for i, r := range rows {
+			if r.userID == reviewerID {
+				rows[i].state = reviewState
+				rows[i].reviewedAt = &now
+				return nil
+			}
+		}
+
+		return storageErrors.ErrReviewerNotAssigned
+	})
+}
+
+func (prs *PullRequestMemoryStorage) SetAuthorReviewState(ctx context.Context, prID string, reviewState models.ReviewState, now time.Time) error {
+	return prs.s.do(func(st *state) error {
+		pr, ok := st.prs[prID]
+		if !ok {
+			return storageErrors.ErrPRNotFound
+		}
+
+		pr.authorReview = reviewState
+		pr.authorReviewedAt = &now
+		st.prs[prID] = pr
+
+		return nil
+	})
+}
+
+func (prs *PullRequestMemoryStorage) GetReviewsByPR(ctx context.Context, prID string) ([]models.Review, error) {
+	var result []models.Review
+
+	err := prs.s.do(func(st *state) error {
+		result = reviews(st, prID)
+		return nil
+	})
+
+	return result, err
+}
+
+func (prs *PullRequestMemoryStorage) ListPullRequests(ctx context.Context, query models.PullRequestQuery) ([]models.PullRequestShort, error) {
+	return prs.listPullRequests(query, func(st *state, pr pullRequest) (models.ReviewState, bool) {
+		return "", true
+	})
+}
+
+func (prs *PullRequestMemoryStorage) GetPullRequestsByReviewer(ctx context.Context, reviewerID string, query models.PullRequestQuery) ([]models.PullRequestShort, error) {
+	return prs.listPullRequests(query, func(st *state, pr pullRequest) (models.ReviewState, bool) {
+		for _, r := range st.reviewers[pr.id] {
+			if r.userID == reviewerID {
+				return r.state, true
+			}
+		}
+		return "", false
+	})
+}
+
+// listPullRequests pages through the pull requests matching query for which
+// include reports true, along with the review state it returns.
+func (prs *PullRequestMemoryStorage) listPullRequests(
+	query models.PullRequestQuery,
+	include func(st *state, pr pullRequest) (models.ReviewState, bool),
+) ([]models.PullRequestShort, error) {
+	result := []models.PullRequestShort{}
+
+	err := prs.s.do(func(st *state) error {
+		all := sortedPullRequests(st)
+		sort.SliceStable(all, func(i, j int) bool {
+			return comparePullRequests(query, all[i], all[j].cursor()) < 0
+		})
+		if query.Desc {
+			slices.Reverse(all)
+		}
+
+		for _, pr := range all {
+			if query.Limit > 0 && len(result) == query.Limit {
+				break
+			}
+			if !matchesQuery(st, pr, query) {
+				continue
+			}
+
+			reviewState, ok := include(st, pr)
+			if !ok {
+				continue
+			}
+
+			result = append(result, models.PullRequestShort{
+				PullRequestID:   pr.id,
+				PullRequestName: pr.name,
+				AuthorID:        pr.authorID,
+				Status:          pr.status,
+				ReviewState:     reviewState,
+				CreatedAt:       pr.createdAt,
+			})
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+func (prs *PullRequestMemoryStorage) GetOpenReviewCountsByTeam(ctx context.Context, teamName string) (map[string]int, error) {
+	result := make(map[string]int)
+
+	err := prs.s.do(func(st *state) error {
+		for _, u := range teamUsers(st, teamName) {
+			result[u.UserID] = 0
+		}
+
+		for prID, rows := range st.reviewers {
+			if st.prs[prID].status != models.StatusOpen {
+				continue
+			}
+			for _, r := range rows {
+				if _, ok := result[r.userID]; ok {
+					result[r.userID]++
+				}
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+func (prs *PullRequestMemoryStorage) GetOpenReviewsByTeam(ctx context.Context, teamName string) ([]models.ReviewAssignment, error) {
+	result := []models.ReviewAssignment{}
+
+	err := prs.s.do(func(st *state) error {
+		ids := make([]string, 0, len(st.prs))
+		for id := range st.prs {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			pr := st.prs[id]
+			if pr.status != models.StatusOpen {
+				continue
+			}
+
+			var rows []models.ReviewAssignment
+			touched := false
+			for _, r := range st.reviewers[id] {
+				team := st.users[r.userID].TeamName
+				touched = touched || team == teamName
+				rows = append(rows, models.ReviewAssignment{
+					PullRequestID: id,
+					AuthorID:      pr.authorID,
+					ReviewerID:    r.userID,
+					ReviewerTeam:  team,
+				})
+			}
+
+			if touched {
+				sort.Slice(rows, func(i, j int) bool {
+					return rows[i].ReviewerID < rows[j].ReviewerID
+				})
+				result = append(result, rows...)
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+func (prs *PullRequestMemoryStorage) CountPullRequestsByUser(ctx context.Context, userID string) (int, error) {
+	var count int
+
+	err := prs.s.do(func(st *state) error {
+		count = countPullRequestsByUser(st, userID)
+		return nil
+	})
+
+	return count, err
+}
+
+func reviewerIDs(st *state, prID string) []string {
+	var ids []string
+	for _, r := range st.reviewers[prID] {
+		ids = append(ids, r.userID)
+	}
+
+	return ids
+}
+
+// reviews returns the verdicts on a pull request ordered by reviewer.
+func reviews(st *state, prID string) []models.Review {
+	var result []models.Review
+	for _, r := range st.reviewers[prID] {
+		review := models.Review{ReviewerID: r.userID, State: r.state}
+		if r.seed != nil {
+			seed := *r.seed
+			review.AssignmentSeed = &seed
+		}
+		if r.reviewedAt != nil {
+			reviewedAt := *r.reviewedAt
+			review.SubmittedAt = &reviewedAt
+		}
+		result = append(result, review)
+	}
+
+	if pr, ok := st.prs[prID]; ok && pr.authorReview != "" {
+		reviewedAt := *pr.authorReviewedAt
+		result = append(result, models.Review{
+			ReviewerID:  pr.authorID,
+			State:       pr.authorReview,
+			SubmittedAt: &reviewedAt,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ReviewerID < result[j].ReviewerID
+	})
+
+	return result
+}
+
+// sortedPullRequests returns all pull requests ordered by creation time.
+func sortedPullRequests(st *state) []pullRequest {
+	result := make([]pullRequest, 0, len(st.prs))
+	for _, pr := range st.prs {
+		result = append(result, pr)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].createdAt.Equal(result[j].createdAt) {
+			return result[i].createdAt.Before(result[j].createdAt)
+		}
+		return result[i].id < result[j].id
+	})
+
+	return result
+}
+
+// matchesQuery reports whether pr passes the filter of query and comes after
+// its cursor.
+func matchesQuery(st *state, pr pullRequest, query models.PullRequestQuery) bool {
+	if len(query.Statuses) > 0 && !slices.Contains(query.Statuses, pr.status) {
+		return false
+	}
+	if query.AuthorID != "" && pr.authorID != query.AuthorID {
+		return false
+	}
+	if query.TeamName != "" && st.users[pr.authorID].TeamName != query.TeamName {
+		return false
+	}
+	if query.ReviewerID != "" && !slices.Contains(reviewerIDs(st, pr.id), query.ReviewerID) {
+		return false
+	}
+	if query.NameContains != "" && !strings.Contains(strings.ToLower(pr.name), strings.ToLower(query.NameContains)) {
+		return false
+	}
+	if query.CreatedFrom != nil && pr.createdAt.Before(*query.CreatedFrom) {
+		return false
+	}
+	if query.CreatedTo != nil && !pr.createdAt.Before(*query.CreatedTo) {
+		return false
+	}
+	if query.After != nil {
+		cmp := comparePullRequests(query, pr, *query.After)
+		if query.Desc {
+			return cmp < 0
+		}
+		return cmp > 0
+	}
+
+	return true
+}
+
+// comparePullRequests orders pr against a cursor by the sort key of query,
+// then by id, ignoring the direction.
+func comparePullRequests(query models.PullRequestQuery, pr pullRequest, c models.PullRequestCursor) int {
+	var cmp int
+	if query.Sort == models.SortByName {
+		cmp = strings.Compare(pr.name, c.PullRequestName)
+	} else {
+		cmp = pr.createdAt.Compare(c.CreatedAt)
+	}
+
+	if cmp != 0 {
+		return cmp
+	}
+	return strings.Compare(pr.id, c.PullRequestID)
+}
+
+func (pr pullRequest) cursor() models.PullRequestCursor {
+	return models.PullRequestCursor{
+		CreatedAt:       pr.createdAt,
+		PullRequestName: pr.name,
+		PullRequestID:   pr.id,
+	}
+}
+
+func countPullRequestsByUser(st *state, userID string) int {
+	count := 0
+	for _, pr := range st.prs {
+		if pr.authorID == userID || slices.Contains(reviewerIDs(st, pr.id), userID) {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+)
+
+type StatsMemoryStorage struct {
+	s *session
+}
+
+func (ss *StatsMemoryStorage) GetUserStats(ctx context.Context, filter models.StatsFilter) ([]models.UserStats, error) {
+	result := []models.UserStats{}
+
+	err := ss.s.do(func(st *state) error {
+		index := map[string]int{}
+		mergeSeconds := map[string]float64{}
+
+		for _, u := range st.users {
+			if filter.TeamName != "" && u.TeamName != filter.TeamName {
+				continue
+			}
+			result = append(result, models.UserStats{
+				UserID:   u.UserID,
+				Username: u.Username,
+				TeamName: u.TeamName,
+				IsActive: u.IsActive,
+			})
+		}
+
+		sort.Slice(result, func(i, j int) bool {
+			if result[i].TeamName != result[j].TeamName {
+				return result[i].TeamName < result[j].TeamName
+			}
+			return result[i].UserID < result[j].UserID
+		})
+		for i, s := range result {
+			index[s.UserID] = i
+		}
+
+		for prID, rows := range st.reviewers {
+			pr := st.prs[prID]
+			if !inWindow(pr.createdAt, filter) {
+				continue
+			}
+
+			for _, r := range rows {
+				i, ok := index[r.userID]
+				if !ok {
+					continue
+				}
+
+				result[i].TotalAssignments++
+				switch pr.status {
+				case models.StatusOpen:
+					result[i].OpenReviews++
+				case models.StatusMerged:
+					result[i].MergedReviews++
+					mergeSeconds[r.userID] += pr.mergedAt.Sub(pr.createdAt).Seconds()
+				}
+			}
+		}
+
+		for _, e := range st.events {
+			if e.EventType != models.EventReassign || !inWindow(e.CreatedAt, filter) {
+				continue
+			}
+			if i, ok := index[e.ReplacementID]; ok {
+				result[i].ReassignedIn++
+			}
+			if i, ok := index[e.UserID]; ok {
+				result[i].ReassignedOut++
+			}
+		}
+
+		for i := range result {
+			if result[i].MergedReviews > 0 {
+				avg := mergeSeconds[result[i].UserID] / float64(result[i].MergedReviews)
+				result[i].AvgTimeToMergeSeconds = &avg
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+func inWindow(t time.Time, filter models.StatsFilter) bool {
+	if filter.From != nil && t.Before(*filter.From) {
+		return false
+	}
+
+	return filter.To == nil || t.Before(*filter.To)
+}
+
+func (ss *StatsMemoryStorage) GetReviewLoad(ctx context.Context, teamName string, since time.Time) (models.LoadDistribution, error) {
+	result := models.LoadDistribution{Users: []models.ReviewLoad{}}
+
+	err := ss.s.do(func(st *state) error {
+		index := map[string]int{}
+		for _, u := range teamUsers(st, teamName) {
+			if !u.IsActive {
+				continue
+			}
+			index[u.UserID] = len(result.Users)
+			result.Users = append(result.Users, models.ReviewLoad{UserID: u.UserID, Username: u.Username})
+		}
+
+		for prID, rows := range st.reviewers {
+			if st.prs[prID].createdAt.Before(since) {
+				continue
+			}
+			for _, r := range rows {
+				if i, ok := index[r.userID]; ok {
+					result.Users[i].Reviews++
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil || len(result.Users) == 0 {
+		return result, err
+	}
+
+	loads := result.Users
+	sort.Slice(loads, func(i, j int) bool {
+		if loads[i].Reviews != loads[j].Reviews {
+			return loads[i].Reviews < loads[j].Reviews
+		}
+		return loads[i].UserID < loads[j].UserID
+	})
+
+	n := float64(len(loads))
+	var squares, ranked float64
+	for i, l := range loads {
+		result.TotalReviews += l.Reviews
+		squares += float64(l.Reviews * l.Reviews)
+		ranked += float64((i + 1) * l.Reviews)
+	}
+
+	result.Members = len(loads)
+	result.Min = loads[0].Reviews
+	result.Max = loads[len(loads)-1].Reviews
+	result.Mean = float64(result.TotalReviews) / n
+	result.StdDev = math.Sqrt(max(0, squares/n-result.Mean*result.Mean))
+	if result.TotalReviews > 0 {
+		result.Gini = 2*ranked/(n*float64(result.TotalReviews)) - (n+1)/n
+	}
+
+	sort.SliceStable(loads, func(i, j int) bool {
+		if loads[i].Reviews != loads[j].Reviews {
+			return loads[i].Reviews > loads[j].Reviews
+		}
+		return loads[i].UserID < loads[j].UserID
+	})
+
+	return result, nil
+}
@@ -0,0 +1,245 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
+)
+
+type TeamMemoryStorage struct {
+	s *session
+}
+
+func (ts *TeamMemoryStorage) CreateTeam(ctx context.Context, teamName string, requiredReviewers int) error {
+	return ts.s.do(func(st *state) error {
+		if _, ok := st.teams[teamName]; !ok {
+			st.teams[teamName] = team{requiredReviewers: requiredReviewers}
+		}
+
+		return nil
+	})
+}
+
+func (ts *TeamMemoryStorage) GetTeamByName(ctx context.Context, teamName string) (models.Team, error) {
+	var result models.Team
+
+	err := ts.s.do(func(st *state) error {
+		t, ok := st.teams[teamName]
+		if !ok {
+			return storageErrors.ErrTeamNotFound
+		}
+
+		result = models.Team{
+			TeamName:          teamName,
+			RequiredReviewers: t.requiredReviewers,
+			FallbackTeams:     append([]string{}, t.fallbackTeams...),
+			MergePolicy:       t.mergePolicy,
+		}
+
+		for _, u := range teamUsers(st, teamName) {
+			result.Members = append(result.Members, models.TeamMember{
+				UserID:   u.UserID,
+				Username: u.Username,
+				IsActive: u.IsActive,
+			})
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+func (ts *TeamMemoryStorage) SetRequiredReviewers(ctx context.Context, teamName string, requiredReviewers int) error {
+	return ts.s.do(func(st *state) error {
+		t, ok := st.teams[teamName]
+		if !ok {
+			return storageErrors.ErrTeamNotFound
+		}
+
+		t.requiredReviewers = requiredReviewers
+		st.teams[teamName] = t
+
+		return nil
+	})
+}
+
+func (ts *TeamMemoryStorage) SetMergePolicy(ctx context.Context, teamName string, policy models.MergePolicy) error {
+	return ts.s.do(func(st *state) error {
+		t, ok := st.teams[teamName]
+		if !ok {
+			return storageErrors.ErrTeamNotFound
+		}
+
+		t.mergePolicy = policy
+		st.teams[teamName] = t
+
+		return nil
+	})
+}
+
+func (ts *TeamMemoryStorage) SetFallbackTeams(ctx context.Context, teamName string, fallbackTeams []string) error {
+	const op = "storage.memory.SetFallbackTeams"
+
+	return ts.s.do(func(st *state) error {
+		t, ok := st.teams[teamName]
+		if !ok {
+			return fmt.Errorf("%s: %w: team %q", op, errForeignKey, teamName)
+		}
+
+		seen := map[string]struct{}{}
+		for _, name := range fallbackTeams {
+			if _, ok := st.teams[name]; !ok {
+				return fmt.Errorf("%s: %w: team %q", op, errForeignKey, name)
+			}
+			if _, dup := seen[name]; dup {
+				return fmt.Errorf("%s: %w: fallback team %q", op, errDuplicateKey, name)
+			}
+			seen[name] = struct{}{}
+		}
+
+		t.fallbackTeams = append([]string(nil), fallbackTeams...)
+		st.teams[teamName] = t
+
+		return nil
+	})
+}
+
+func (ts *TeamMemoryStorage) GetUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
+	var users []models.User
+
+	err := ts.s.do(func(st *state) error {
+		users = teamUsers(st, teamName)
+		return nil
+	})
+
+	return users, err
+}
+
+func (ts *TeamMemoryStorage) AdvanceRotation(ctx context.Context, teamName string, step int) (int64, error) {
+	const op = "storage.memory.AdvanceRotation"
+
+	var position int64
+
+	err := ts.s.do(func(st *state) error {
+		if _, ok := st.teams[teamName]; !ok {
+			return fmt.Errorf("%s: %w: team %q", op, errForeignKey, teamName)
+		}
+
+		position = st.rotations[teamName]
+		st.rotations[teamName] = position + int64(step)
+
+		return nil
+	})
+
+	return position, err
+}
+
+func (ts *TeamMemoryStorage) ListTeams(ctx context.Context) ([]models.TeamSummary, error) {
+	result := []models.TeamSummary{}
+
+	err := ts.s.do(func(st *state) error {
+		for name, t := range st.teams {
+			summary := models.TeamSummary{TeamName: name, RequiredReviewers: t.requiredReviewers}
+			for _, u := range teamUsers(st, name) {
+				summary.Members++
+				if u.IsActive {
+					summary.ActiveMembers++
+				}
+			}
+			result = append(result, summary)
+		}
+
+		sort.Slice(result, func(i, j int) bool {
+			return result[i].TeamName < result[j].TeamName
+		})
+
+		return nil
+	})
+
+	return result, err
+}
+
+func (ts *TeamMemoryStorage) GetDependentTeams(ctx context.Context, teamName string) ([]string, error) {
+	var result []string
+
+	err := ts.s.do(func(st *state) error {
+		result = dependentTeams(st, teamName)
+		return nil
+	})
+
+	return result, err
+}
+
+func (ts *TeamMemoryStorage) RenameTeam(ctx context.Context, teamName, newName string) error {
+	const op = "storage.memory.RenameTeam"
+
+	return ts.s.do(func(st *state) error {
+		t, ok := st.teams[teamName]
+		if !ok {
+			return storageErrors.ErrTeamNotFound
+		}
+		if _, exists := st.teams[newName]; exists {
+			return fmt.Errorf("%s: %w: team %q", op, errDuplicateKey, newName)
+		}
+
+		st.teams[newName] = t
+		delete(st.teams, teamName)
+
+		if position, ok := st.rotations[teamName]; ok {
+			st.rotations[newName] = position
+			delete(st.rotations, teamName)
+		}
+
+		for id, u := range st.users {
+			if u.TeamName == teamName {
+				u.TeamName = newName
+				st.users[id] = u
+			}
+		}
+
+		for name, other := range st.teams {
+			if i := slices.Index(other.fallbackTeams, teamName); i >= 0 {
+				other.fallbackTeams = slices.Clone(other.fallbackTeams)
+				other.fallbackTeams[i] = newName
+				st.teams[name] = other
+			}
+		}
+
+		return nil
+	})
+}
+
+func (ts *TeamMemoryStorage) DeleteTeam(ctx context.Context, teamName string) error {
+	const op = "storage.memory.DeleteTeam"
+
+	return ts.s.do(func(st *state) error {
+		if _, ok := st.teams[teamName]; !ok {
+			return storageErrors.ErrTeamNotFound
+		}
+		if len(teamUsers(st, teamName)) > 0 || len(dependentTeams(st, teamName)) > 0 {
+			return fmt.Errorf("%s: %w: team %q", op, errForeignKey, teamName)
+		}
+
+		delete(st.teams, teamName)
+		delete(st.rotations, teamName)
+
+		return nil
+	})
+}
+
+func dependentTeams(st *state, teamName string) []string {
+	result := []string{}
+	for name, t := range st.teams {
+		if slices.Contains(t.fallbackTeams, teamName) {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+
+	return result
+}
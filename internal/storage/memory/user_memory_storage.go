@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
+)
+
+type UserMemoryStorage struct {
+	s *session
+}
+
+func (us *UserMemoryStorage) CreateUser(ctx context.Context, userID, username, teamName string) error {
+	const op = "storage.memory.CreateUser"
+
+	return us.s.do(func(st *state) error {
+		if _, ok := st.users[userID]; ok {
+			return fmt.Errorf("%s: %w: user %q", op, errDuplicateKey, userID)
+		}
+		if _, ok := st.teams[teamName]; !ok {
+			return fmt.Errorf("%s: %w: team %q", op, errForeignKey, teamName)
+		}
+
+		st.users[userID] = models.User{
+			UserID:   userID,
+			Username: username,
+			TeamName: teamName,
+			IsActive: true,
+		}
+
+		return nil
+	})
+}
+
+func (us *UserMemoryStorage) GetUserByID(ctx context.Context, userID string) (models.User, error) {
+	var user models.User
+
+	err := us.s.do(func(st *state) error {
+		u, ok := st.users[userID]
+		if !ok {
+			return storageErrors.ErrUserNotFound
+		}
+
+		user = u
+		return nil
+	})
+
+	return user, err
+}
+
+func (us *UserMemoryStorage) SetUserActiveStatus(ctx context.Context, userID string, isActive bool) error {
+	return us.s.do(func(st *state) error {
+		if u, ok := st.users[userID]; ok {
+			u.IsActive = isActive
+			st.users[userID] = u
+		}
+
+		return nil
+	})
+}
+
+func (us *UserMemoryStorage) SetTeamActiveStatus(ctx context.Context, teamName string, isActive bool) ([]string, error) {
+	changed := []string{}
+
+	err := us.s.do(func(st *state) error {
+		for _, u := range teamUsers(st, teamName) {
+			if u.IsActive == isActive {
+				continue
+			}
+
+			u.IsActive = isActive
+			st.users[u.UserID] = u
+			changed = append(changed, u.UserID)
+		}
+
+		return nil
+	})
+
+	return changed, err
+}
+
+func (us *UserMemoryStorage) SetUserTeam(ctx context.Context, userID, teamName string) error {
+	const op = "storage.memory.SetUserTeam"
+
+	return us.s.do(func(st *state) error {
+		u, ok := st.users[userID]
+		if !ok {
+			return nil
+		}
+		if _, ok := st.teams[teamName]; !ok {
+			return fmt.Errorf("%s: %w: team %q", op, errForeignKey, teamName)
+		}
+
+		u.TeamName = teamName
+		st.users[userID] = u
+
+		return nil
+	})
+}
+
+func (us *UserMemoryStorage) GetActiveUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
+	var users []models.User
+
+	err := us.s.do(func(st *state) error {
+		for _, u := range teamUsers(st, teamName) {
+			if u.IsActive {
+				users = append(users, u)
+			}
+		}
+
+		return nil
+	})
+
+	return users, err
+}
+
+// teamUsers returns the members of a team ordered by user_id.
+func teamUsers(st *state, teamName string) []models.User {
+	var users []models.User
+
+	for _, u := range st.users {
+		if u.TeamName == teamName {
+			users = append(users, u)
+		}
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].UserID < users[j].UserID
+	})
+
+	return users
+}
+
+func (us *UserMemoryStorage) DeleteUser(ctx context.Context, userID string) error {
+	const op = "storage.memory.DeleteUser"
+
+	return us.s.do(func(st *state) error {
+		if _, ok := st.users[userID]; !ok {
+			return storageErrors.ErrUserNotFound
+		}
+
+		if countPullRequestsByUser(st, userID) > 0 {
+			return fmt.Errorf("%s: %w: user %q", op, errForeignKey, userID)
+		}
+
+		delete(st.users, userID)
+
+		return nil
+	})
+}
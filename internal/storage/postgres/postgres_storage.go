@@ -1,14 +1,27 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	_ "github.com/lib/pq"
+	"github.com/pacahar/pr-reviewer-assignment/internal/migrator"
 	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+	"github.com/pacahar/pr-reviewer-assignment/migrations"
 )
 
-func NewPostgresStorage(dsn string) (*storage.Storage, error) {
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// NewPostgresStorage connects to dsn and fails if the schema is newer than the
+// binary. With autoMigrate set it first applies pending migrations.
+func NewPostgresStorage(dsn string, autoMigrate bool) (*storage.Storage, error) {
 	const op = "storage.postgres.NewPostgresStorage"
 
 	db, err := sql.Open("postgres", dsn)
@@ -17,16 +30,127 @@ func NewPostgresStorage(dsn string) (*storage.Storage, error) {
 	}
 
 	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	m, err := migrator.New(db, migrations.FS)
+	if err != nil {
+		db.Close()
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	teamStorage := &TeamPostgresStorage{db: db}
-	userStorage := &UserPostgresStorage{db: db}
-	prStorage := &PullRequestPostgresStorage{db: db}
+	if autoMigrate {
+		_, err = m.Up(context.Background())
+	} else {
+		err = m.Check(context.Background())
+	}
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	st := newStorage(db, &PostgresTransactor{db: db})
+	st.Closer = db
+
+	return st, nil
+}
+
+func newStorage(q querier, transactor storage.Transactor) *storage.Storage {
+	teamStorage := &TeamPostgresStorage{db: q}
+	userStorage := &UserPostgresStorage{db: q}
+	prStorage := &PullRequestPostgresStorage{db: q}
+	eventStorage := &EventPostgresStorage{db: q}
+	statsStorage := &StatsPostgresStorage{db: q}
 
 	return &storage.Storage{
 		UserStorage:        userStorage,
 		TeamStorage:        teamStorage,
 		PullRequestStorage: prStorage,
-	}, nil
+		EventStorage:       eventStorage,
+		StatsStorage:       statsStorage,
+		Transactor:         transactor,
+	}
+}
+
+type PostgresTransactor struct {
+	db *sql.DB
+}
+
+func (t *PostgresTransactor) WithTx(ctx context.Context, fn func(st *storage.Storage) error) error {
+	const op = "storage.postgres.WithTx"
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	txStorage := newStorage(tx, nil)
+	txStorage.Transactor = &txTransactor{st: txStorage}
+
+	if err := fn(txStorage); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// txTransactor is used by storages that already run inside a transaction.
+type txTransactor struct {
+	st *storage.Storage
+}
+
+func (t *txTransactor) WithTx(ctx context.Context, fn func(st *storage.Storage) error) error {
+	return fn(t.st)
+}
+
+// inTx runs fn in a transaction unless q already is one.
+func inTx(ctx context.Context, q querier, fn func(q querier) error) error {
+	db, ok := q.(*sql.DB)
+	if !ok {
+		return fn(q)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// batchSize bounds the rows of one multi-row statement, keeping it well under
+// the driver's parameter limit.
+const batchSize = 1000
+
+// placeholders returns rows comma-separated tuples of width numbered
+// parameters: ($1, $2), ($3, $4), ...
+func placeholders(rows, width int) string {
+	var b strings.Builder
+
+	for i := 0; i < rows; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for j := 0; j < width; j++ {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "$%d", i*width+j+1)
+		}
+		b.WriteByte(')')
+	}
+
+	return b.String()
 }
@@ -0,0 +1,13 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage/storagetest"
+)
+
+// TestPostgresStorage runs against the database in STORAGETEST_POSTGRES_DSN
+// and is skipped when it is unset.
+func TestPostgresStorage(t *testing.T) {
+	storagetest.Run(t, storagetest.Postgres)
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pacahar/pr-reviewer-assignment/internal/models"
@@ -11,10 +14,10 @@ import (
 )
 
 type PullRequestPostgresStorage struct {
-	db *sql.DB
+	db querier
 }
 
-func (prs *PullRequestPostgresStorage) CreatePullRequest(ctx context.Context, prID, prName, authorID string) error {
+func (prs *PullRequestPostgresStorage) CreatePullRequest(ctx context.Context, prID, prName, authorID string, status models.PullRequestStatus) error {
 	_, err := prs.db.ExecContext(ctx, `
 		INSERT INTO pull_requests
 		(pull_request_id, pull_request_name, author_id, status) 
@@ -22,7 +25,7 @@ func (prs *PullRequestPostgresStorage) CreatePullRequest(ctx context.Context, pr
 		prID,
 		prName,
 		authorID,
-		"OPEN",
+		status,
 	)
 	return err
 }
@@ -69,14 +72,40 @@ func (prs *PullRequestPostgresStorage) GetPullRequestByID(ctx context.Context, p
 
 	pr.AssignedReviewers = reviewers
 
+	reviews, err := prs.GetReviewsByPR(ctx, prID)
+	if err != nil {
+		return models.PullRequest{}, err
+	}
+
+	pr.Reviews = reviews
+
 	return pr, nil
 }
 
-func (prs *PullRequestPostgresStorage) SetPullRequestStatus(ctx context.Context, prID, status string, now time.Time) error {
+// LockPullRequest takes a row lock on the pull request that is held until the
+// surrounding transaction ends. Outside of WithTx it has no lasting effect.
+func (prs *PullRequestPostgresStorage) LockPullRequest(ctx context.Context, prID string) error {
+	var id string
+
+	err := prs.db.QueryRowContext(ctx, `
+		SELECT pull_request_id
+		FROM pull_requests
+		WHERE pull_request_id = $1
+		FOR UPDATE;`,
+		prID,
+	).Scan(&id)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return storageErrors.ErrPRNotFound
+	}
+	return err
+}
+
+func (prs *PullRequestPostgresStorage) SetPullRequestStatus(ctx context.Context, prID string, status models.PullRequestStatus, now time.Time) error {
 	// prs.GetPullRequestByID(ctx, prID)
 
 	var mergedAt *time.Time
-	if status == "MERGED" {
+	if status == models.StatusMerged {
 		mergedAt = &now
 	}
 
@@ -92,17 +121,54 @@ func (prs *PullRequestPostgresStorage) SetPullRequestStatus(ctx context.Context,
 	return err
 }
 
-func (prs *PullRequestPostgresStorage) AddReviewer(ctx context.Context, prID, userID string) error {
+func (prs *PullRequestPostgresStorage) AddReviewer(ctx context.Context, prID, userID string, seed *int64) error {
 	_, err := prs.db.ExecContext(ctx, `
-		INSERT INTO pr_reviewers (pull_request_id, reviewer_id)
-		VALUES ($1, $2)
+		INSERT INTO pr_reviewers (pull_request_id, reviewer_id, assignment_seed)
+		VALUES ($1, $2, $3)
 		ON CONFLICT DO NOTHING;`,
 		prID,
 		userID,
+		seed,
 	)
 	return err
 }
 
+// ReplaceReviewers applies replacements with one DELETE and one INSERT per
+// batch instead of two statements per reviewer.
+func (prs *PullRequestPostgresStorage) ReplaceReviewers(ctx context.Context, replacements []models.ReviewerReplacement) error {
+	return inTx(ctx, prs.db, func(q querier) error {
+		for start := 0; start < len(replacements); start += batchSize {
+			batch := replacements[start:min(start+batchSize, len(replacements))]
+
+			removed := make([]any, 0, 2*len(batch))
+			added := make([]any, 0, 3*len(batch))
+			for _, r := range batch {
+				removed = append(removed, r.PullRequestID, r.OldReviewerID)
+				added = append(added, r.PullRequestID, r.NewReviewerID, r.Seed)
+			}
+
+			if _, err := q.ExecContext(ctx, `
+				DELETE FROM pr_reviewers
+				WHERE (pull_request_id, reviewer_id) IN (VALUES `+placeholders(len(batch), 2)+`);`,
+				removed...,
+			); err != nil {
+				return err
+			}
+
+			if _, err := q.ExecContext(ctx, `
+				INSERT INTO pr_reviewers (pull_request_id, reviewer_id, assignment_seed)
+				VALUES `+placeholders(len(batch), 3)+`
+				ON CONFLICT DO NOTHING;`,
+				added...,
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
 func (prs *PullRequestPostgresStorage) RemoveReviewer(ctx context.Context, prID, userID string) error {
 	_, err := prs.db.ExecContext(ctx, `
 		DELETE FROM pr_reviewers
@@ -139,24 +205,159 @@ func (prs *PullRequestPostgresStorage) GetReviewersByPR(ctx context.Context, prI
 	return reviewers, nil
 }
 
-func (prs *PullRequestPostgresStorage) GetPullRequestsByReviewer(ctx context.Context, reviewerID string) ([]models.PullRequestShort, error) {
+func (prs *PullRequestPostgresStorage) SetReviewState(ctx context.Context, prID, reviewerID string, state models.ReviewState, now time.Time) error {
+	res, err := prs.db.ExecContext(ctx, `
+		UPDATE pr_reviewers
+		SET review_state = $1,
+		    reviewed_at = $2
+		WHERE pull_request_id = $3 AND reviewer_id = $4;`,
+		state,
+		now,
+		prID,
+		reviewerID,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storageErrors.ErrReviewerNotAssigned
+	}
+
+	return nil
+}
+
+// SetAuthorReviewState records the author's own verdict, which is kept apart
+// from the assigned reviewers.
+func (prs *PullRequestPostgresStorage) SetAuthorReviewState(ctx context.Context, prID string, state models.ReviewState, now time.Time) error {
+	res, err := prs.db.ExecContext(ctx, `
+		UPDATE pull_requests
+		SET author_review_state = $1,
+		    author_reviewed_at = $2
+		WHERE pull_request_id = $3;`,
+		state,
+		now,
+		prID,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storageErrors.ErrPRNotFound
+	}
+
+	return nil
+}
+
+// GetReviewsByPR returns the assigned reviewers' reviews and, once given, the
+// author's own verdict.
+func (prs *PullRequestPostgresStorage) GetReviewsByPR(ctx context.Context, prID string) ([]models.Review, error) {
 	rows, err := prs.db.QueryContext(ctx, `
+		SELECT reviewer_id, review_state, assignment_seed, reviewed_at
+		FROM pr_reviewers
+		WHERE pull_request_id = $1
+		UNION ALL
+		SELECT author_id, author_review_state, NULL::BIGINT, author_reviewed_at
+		FROM pull_requests
+		WHERE pull_request_id = $1 AND author_review_state IS NOT NULL
+		ORDER BY reviewer_id;`,
+		prID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []models.Review
+
+	for rows.Next() {
+		var review models.Review
+		var seed sql.NullInt64
+		var reviewedAt sql.NullTime
+
+		if err := rows.Scan(&review.ReviewerID, &review.State, &seed, &reviewedAt); err != nil {
+			return nil, err
+		}
+
+		if seed.Valid {
+			s := seed.Int64
+			review.AssignmentSeed = &s
+		}
+
+		if reviewedAt.Valid {
+			t := reviewedAt.Time
+			review.SubmittedAt = &t
+		}
+
+		reviews = append(reviews, review)
+	}
+
+	return reviews, rows.Err()
+}
+
+func (prs *PullRequestPostgresStorage) ListPullRequests(ctx context.Context, query models.PullRequestQuery) ([]models.PullRequestShort, error) {
+	return prs.listPullRequests(ctx, `
 		SELECT pr.pull_request_id,
 		       pr.pull_request_name,
 		       pr.author_id,
-		       pr.status
+		       pr.status,
+		       '',
+		       pr.created_at
+		FROM pull_requests pr`,
+		nil,
+		query,
+	)
+}
+
+func (prs *PullRequestPostgresStorage) GetPullRequestsByReviewer(ctx context.Context, reviewerID string, query models.PullRequestQuery) ([]models.PullRequestShort, error) {
+	return prs.listPullRequests(ctx, `
+		SELECT pr.pull_request_id,
+		       pr.pull_request_name,
+		       pr.author_id,
+		       pr.status,
+		       r.review_state,
+		       pr.created_at
 		FROM pull_requests pr
 		JOIN pr_reviewers r
 		    ON pr.pull_request_id = r.pull_request_id
-		WHERE r.reviewer_id = $1;`,
-		reviewerID,
+		   AND r.reviewer_id = $1`,
+		[]any{reviewerID},
+		query,
 	)
+}
+
+// listPullRequests completes base, a select of short pull requests from
+// pull_requests aliased pr, with the filter, order and limit of query.
+func (prs *PullRequestPostgresStorage) listPullRequests(ctx context.Context, base string, args []any, query models.PullRequestQuery) ([]models.PullRequestShort, error) {
+	q := base
+
+	if conditions := pullRequestConditions(query, &args); len(conditions) > 0 {
+		q += "\n\t\tWHERE " + strings.Join(conditions, "\n\t\t  AND ")
+	}
+
+	key, dir := pullRequestSortKey(query)
+	q += fmt.Sprintf("\n\t\tORDER BY %s %s, pr.pull_request_id %s", key, dir, dir)
+
+	if query.Limit > 0 {
+		q += "\n\t\tLIMIT " + strconv.Itoa(query.Limit)
+	}
+
+	rows, err := prs.db.QueryContext(ctx, q+";", args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var result []models.PullRequestShort
+	result := []models.PullRequestShort{}
 
 	for rows.Next() {
 		var pr models.PullRequestShort
@@ -166,6 +367,8 @@ func (prs *PullRequestPostgresStorage) GetPullRequestsByReviewer(ctx context.Con
 			&pr.PullRequestName,
 			&pr.AuthorID,
 			&pr.Status,
+			&pr.ReviewState,
+			&pr.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -174,5 +377,189 @@ func (prs *PullRequestPostgresStorage) GetPullRequestsByReviewer(ctx context.Con
 		result = append(result, pr)
 	}
 
+	return result, rows.Err()
+}
+
+func (prs *PullRequestPostgresStorage) GetOpenReviewCountsByTeam(ctx context.Context, teamName string) (map[string]int, error) {
+	rows, err := prs.db.QueryContext(ctx, `
+		SELECT u.user_id,
+		       COUNT(pr.pull_request_id)
+		FROM users u
+		LEFT JOIN pr_reviewers r
+		    ON r.reviewer_id = u.user_id
+		LEFT JOIN pull_requests pr
+		    ON pr.pull_request_id = r.pull_request_id AND pr.status = 'OPEN'
+		WHERE u.team_name = $1
+		GROUP BY u.user_id;`,
+		teamName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]int)
+
+	for rows.Next() {
+		var userID string
+		var count int
+
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, err
+		}
+
+		result[userID] = count
+	}
+
 	return result, nil
 }
+
+// GetOpenReviewsByTeam returns every reviewer of the OPEN pull requests that
+// have at least one reviewer from teamName, ordered by pull request and
+// reviewer. The pull requests stay locked until the
+// surrounding transaction ends.
+func (prs *PullRequestPostgresStorage) GetOpenReviewsByTeam(ctx context.Context, teamName string) ([]models.ReviewAssignment, error) {
+	rows, err := prs.db.QueryContext(ctx, `
+		SELECT pr.pull_request_id,
+		       pr.author_id,
+		       r.reviewer_id,
+		       u.team_name
+		FROM pull_requests pr
+		JOIN pr_reviewers r
+		    ON r.pull_request_id = pr.pull_request_id
+		JOIN users u
+		    ON u.user_id = r.reviewer_id
+		WHERE pr.status = 'OPEN'
+		  AND pr.pull_request_id IN (
+		      SELECT tr.pull_request_id
+		      FROM pr_reviewers tr
+		      JOIN users tu
+		          ON tu.user_id = tr.reviewer_id
+		      WHERE tu.team_name = $1
+		  )
+		ORDER BY pr.pull_request_id, r.reviewer_id
+		FOR UPDATE OF pr;`,
+		teamName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []models.ReviewAssignment{}
+
+	for rows.Next() {
+		var a models.ReviewAssignment
+
+		if err := rows.Scan(&a.PullRequestID, &a.AuthorID, &a.ReviewerID, &a.ReviewerTeam); err != nil {
+			return nil, err
+		}
+
+		result = append(result, a)
+	}
+
+	return result, rows.Err()
+}
+
+// CountPullRequestsByUser counts the pull requests the user authored or
+// is a reviewer of.
+func (prs *PullRequestPostgresStorage) CountPullRequestsByUser(ctx context.Context, userID string) (int, error) {
+	var count int
+
+	err := prs.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM pull_requests pr
+		WHERE pr.author_id = $1
+		   OR EXISTS (
+		       SELECT 1
+		       FROM pr_reviewers r
+		       WHERE r.pull_request_id = pr.pull_request_id
+		         AND r.reviewer_id = $1
+		   );`,
+		userID,
+	).Scan(&count)
+
+	return count, err
+}
+
+// pullRequestConditions renders the filter and cursor of query as conditions
+// on pull_requests aliased pr, appending their parameters to args.
+func pullRequestConditions(query models.PullRequestQuery, args *[]any) []string {
+	arg := func(v any) string {
+		*args = append(*args, v)
+		return "$" + strconv.Itoa(len(*args))
+	}
+
+	var conditions []string
+
+	if len(query.Statuses) > 0 {
+		placeholders := make([]string, 0, len(query.Statuses))
+		for _, status := range query.Statuses {
+			placeholders = append(placeholders, arg(status))
+		}
+		conditions = append(conditions, "pr.status IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if query.AuthorID != "" {
+		conditions = append(conditions, "pr.author_id = "+arg(query.AuthorID))
+	}
+	if query.TeamName != "" {
+		conditions = append(conditions, `EXISTS (
+		      SELECT 1
+		      FROM users fu
+		      WHERE fu.user_id = pr.author_id
+		        AND fu.team_name = `+arg(query.TeamName)+`
+		  )`)
+	}
+	if query.ReviewerID != "" {
+		conditions = append(conditions, `EXISTS (
+		      SELECT 1
+		      FROM pr_reviewers fr
+		      WHERE fr.pull_request_id = pr.pull_request_id
+		        AND fr.reviewer_id = `+arg(query.ReviewerID)+`
+		  )`)
+	}
+	if query.NameContains != "" {
+		conditions = append(conditions, "pr.pull_request_name ILIKE "+arg("%"+likeEscaper.Replace(query.NameContains)+"%")+` ESCAPE '\'`)
+	}
+	if query.CreatedFrom != nil {
+		conditions = append(conditions, "pr.created_at >= "+arg(query.CreatedFrom))
+	}
+	if query.CreatedTo != nil {
+		conditions = append(conditions, "pr.created_at < "+arg(query.CreatedTo))
+	}
+	if after := query.After; after != nil {
+		key, dir := pullRequestSortKey(query)
+
+		var value any = after.CreatedAt
+		if query.Sort == models.SortByName {
+			value = after.PullRequestName
+		}
+
+		op := ">"
+		if dir == "DESC" {
+			op = "<"
+		}
+
+		conditions = append(conditions, fmt.Sprintf("(%s, pr.pull_request_id) %s (%s, %s)",
+			key, op, arg(value), arg(after.PullRequestID)))
+	}
+
+	return conditions
+}
+
+func pullRequestSortKey(query models.PullRequestQuery) (key, dir string) {
+	key = "pr.created_at"
+	if query.Sort == models.SortByName {
+		// Byte order, like the other backends, whatever the database locale.
+		key = `pr.pull_request_name COLLATE "C"`
+	}
+
+	dir = "ASC"
+	if query.Desc {
+		dir = "DESC"
+	}
+
+	return key, dir
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
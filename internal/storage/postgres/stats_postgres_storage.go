@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+)
+
+type StatsPostgresStorage struct {
+	db querier
+}
+
+// GetUserStats aggregates reviews and reassignments per user in one query.
+// Users without reviews in the window are listed with zero counts.
+func (ss *StatsPostgresStorage) GetUserStats(ctx context.Context, filter models.StatsFilter) ([]models.UserStats, error) {
+	rows, err := ss.db.QueryContext(ctx, `
+		WITH reviews AS (
+			SELECT r.reviewer_id,
+			       COUNT(*) AS total,
+			       COUNT(*) FILTER (WHERE pr.status = 'OPEN') AS open,
+			       COUNT(*) FILTER (WHERE pr.status = 'MERGED') AS merged,
+			       AVG(EXTRACT(EPOCH FROM pr.merged_at - pr.created_at))
+			           FILTER (WHERE pr.status = 'MERGED') AS avg_merge
+			FROM pr_reviewers r
+			JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+			WHERE ($2::timestamptz IS NULL OR pr.created_at >= $2)
+			  AND ($3::timestamptz IS NULL OR pr.created_at < $3)
+			GROUP BY r.reviewer_id
+		), moves AS (
+			SELECT m.user_id,
+			       COUNT(*) FILTER (WHERE m.moved_in) AS moved_in,
+			       COUNT(*) FILTER (WHERE NOT m.moved_in) AS moved_out
+			FROM (
+				SELECT replacement_id AS user_id, TRUE AS moved_in, created_at
+				FROM assignment_events
+				WHERE event_type = 'REASSIGN'
+				UNION ALL
+				SELECT user_id, FALSE, created_at
+				FROM assignment_events
+				WHERE event_type = 'REASSIGN'
+			) m
+			WHERE ($2::timestamptz IS NULL OR m.created_at >= $2)
+			  AND ($3::timestamptz IS NULL OR m.created_at < $3)
+			GROUP BY m.user_id
+		)
+		SELECT u.user_id,
+		       u.username,
+		       u.team_name,
+		       u.is_active,
+		       COALESCE(rv.total, 0),
+		       COALESCE(rv.open, 0),
+		       COALESCE(rv.merged, 0),
+		       COALESCE(mv.moved_in, 0),
+		       COALESCE(mv.moved_out, 0),
+		       rv.avg_merge
+		FROM users u
+		LEFT JOIN reviews rv ON rv.reviewer_id = u.user_id
+		LEFT JOIN moves mv ON mv.user_id = u.user_id
+		WHERE $1 = '' OR u.team_name = $1
+		ORDER BY u.team_name, u.user_id;`,
+		filter.TeamName,
+		filter.From,
+		filter.To,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []models.UserStats{}
+
+	for rows.Next() {
+		var (
+			s        models.UserStats
+			avgMerge sql.NullFloat64
+		)
+
+		err := rows.Scan(
+			&s.UserID,
+			&s.Username,
+			&s.TeamName,
+			&s.IsActive,
+			&s.TotalAssignments,
+			&s.OpenReviews,
+			&s.MergedReviews,
+			&s.ReassignedIn,
+			&s.ReassignedOut,
+			&avgMerge,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if avgMerge.Valid {
+			s.AvgTimeToMergeSeconds = &avgMerge.Float64
+		}
+
+		result = append(result, s)
+	}
+
+	return result, rows.Err()
+}
+
+// GetReviewLoad computes the load of every active member of the team and the
+// distribution summary in a single pass. The Gini coefficient uses the
+// rank-weighted form 2*sum(rank*x)/(n*sum(x)) - (n+1)/n over loads sorted
+// ascending.
+func (ss *StatsPostgresStorage) GetReviewLoad(ctx context.Context, teamName string, since time.Time) (models.LoadDistribution, error) {
+	rows, err := ss.db.QueryContext(ctx, `
+		WITH loads AS (
+			SELECT u.user_id,
+			       u.username,
+			       COUNT(pr.pull_request_id) AS reviews
+			FROM users u
+			LEFT JOIN pr_reviewers r ON r.reviewer_id = u.user_id
+			LEFT JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+			                          AND pr.created_at >= $2
+			WHERE u.team_name = $1 AND u.is_active
+			GROUP BY u.user_id, u.username
+		), ranked AS (
+			SELECT user_id,
+			       username,
+			       reviews,
+			       ROW_NUMBER() OVER (ORDER BY reviews, user_id) AS rank
+			FROM loads
+		)
+		SELECT user_id,
+		       username,
+		       reviews,
+		       COUNT(*) OVER (),
+		       SUM(reviews) OVER (),
+		       MIN(reviews) OVER (),
+		       MAX(reviews) OVER (),
+		       AVG(reviews) OVER (),
+		       STDDEV_POP(reviews) OVER (),
+		       CASE WHEN SUM(reviews) OVER () = 0 THEN 0
+		            ELSE 2.0 * SUM(rank * reviews) OVER () / (COUNT(*) OVER () * SUM(reviews) OVER ())
+		                 - (COUNT(*) OVER () + 1.0) / COUNT(*) OVER ()
+		       END
+		FROM ranked
+		ORDER BY reviews DESC, user_id;`,
+		teamName,
+		since,
+	)
+	if err != nil {
+		return models.LoadDistribution{}, err
+	}
+	defer rows.Close()
+
+	return scanReviewLoad(rows)
+}
+
+func scanReviewLoad(rows *sql.Rows) (models.LoadDistribution, error) {
+	result := models.LoadDistribution{Users: []models.ReviewLoad{}}
+
+	for rows.Next() {
+		var load models.ReviewLoad
+
+		err := rows.Scan(
+			&load.UserID,
+			&load.Username,
+			&load.Reviews,
+			&result.Members,
+			&result.TotalReviews,
+			&result.Min,
+			&result.Max,
+			&result.Mean,
+			&result.StdDev,
+			&result.Gini,
+		)
+		if err != nil {
+			return models.LoadDistribution{}, err
+		}
+
+		result.Users = append(result.Users, load)
+	}
+
+	return result, rows.Err()
+}
@@ -10,15 +10,16 @@ import (
 )
 
 type TeamPostgresStorage struct {
-	db *sql.DB
+	db querier
 }
 
-func (ts *TeamPostgresStorage) CreateTeam(ctx context.Context, teamName string) error {
+func (ts *TeamPostgresStorage) CreateTeam(ctx context.Context, teamName string, requiredReviewers int) error {
 	_, err := ts.db.ExecContext(ctx, `
-		INSERT INTO teams (team_name)
-		VALUES ($1)
+		INSERT INTO teams (team_name, required_reviewers)
+		VALUES ($1, $2)
 		ON CONFLICT (team_name) DO NOTHING;`,
 		teamName,
+		requiredReviewers,
 	)
 
 	return err
@@ -27,10 +28,24 @@ func (ts *TeamPostgresStorage) CreateTeam(ctx context.Context, teamName string)
 func (ts *TeamPostgresStorage) GetTeamByName(ctx context.Context, teamName string) (models.Team, error) {
 	var team models.Team
 
-	err := ts.db.QueryRowContext(ctx,
-		`SELECT team_name FROM teams WHERE team_name = $1;`,
+	err := ts.db.QueryRowContext(ctx, `
+		SELECT team_name,
+		       required_reviewers,
+		       min_approvals,
+		       block_on_changes_requested,
+		       forbid_author_sole_approver,
+		       require_all_reviewers
+		FROM teams
+		WHERE team_name = $1;`,
 		teamName,
-	).Scan(&team.TeamName)
+	).Scan(
+		&team.TeamName,
+		&team.RequiredReviewers,
+		&team.MergePolicy.MinApprovals,
+		&team.MergePolicy.BlockOnChangesRequested,
+		&team.MergePolicy.ForbidAuthorSoleApprover,
+		&team.MergePolicy.RequireAllReviewers,
+	)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -58,9 +73,120 @@ func (ts *TeamPostgresStorage) GetTeamByName(ctx context.Context, teamName strin
 		team.Members = append(team.Members, u)
 	}
 
+	fallbackTeams, err := ts.getFallbackTeams(ctx, teamName)
+	if err != nil {
+		return models.Team{}, err
+	}
+
+	team.FallbackTeams = fallbackTeams
+
 	return team, nil
 }
 
+func (ts *TeamPostgresStorage) getFallbackTeams(ctx context.Context, teamName string) ([]string, error) {
+	rows, err := ts.db.QueryContext(ctx, `
+		SELECT fallback_team_name
+		FROM team_fallbacks
+		WHERE team_name = $1
+		ORDER BY priority;`,
+		teamName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []string{}
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		result = append(result, name)
+	}
+
+	return result, nil
+}
+
+func (ts *TeamPostgresStorage) SetFallbackTeams(ctx context.Context, teamName string, fallbackTeams []string) error {
+	return inTx(ctx, ts.db, func(q querier) error {
+		if _, err := q.ExecContext(ctx, `
+			DELETE FROM team_fallbacks
+			WHERE team_name = $1;`,
+			teamName,
+		); err != nil {
+			return err
+		}
+
+		for i, fallback := range fallbackTeams {
+			if _, err := q.ExecContext(ctx, `
+				INSERT INTO team_fallbacks (team_name, fallback_team_name, priority)
+				VALUES ($1, $2, $3);`,
+				teamName,
+				fallback,
+				i,
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (ts *TeamPostgresStorage) SetRequiredReviewers(ctx context.Context, teamName string, requiredReviewers int) error {
+	res, err := ts.db.ExecContext(ctx, `
+		UPDATE teams
+		SET required_reviewers = $1
+		WHERE team_name = $2;`,
+		requiredReviewers,
+		teamName,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return storageErrors.ErrTeamNotFound
+	}
+
+	return nil
+}
+
+func (ts *TeamPostgresStorage) SetMergePolicy(ctx context.Context, teamName string, policy models.MergePolicy) error {
+	res, err := ts.db.ExecContext(ctx, `
+		UPDATE teams
+		SET min_approvals = $1,
+		    block_on_changes_requested = $2,
+		    forbid_author_sole_approver = $3,
+		    require_all_reviewers = $4
+		WHERE team_name = $5;`,
+		policy.MinApprovals,
+		policy.BlockOnChangesRequested,
+		policy.ForbidAuthorSoleApprover,
+		policy.RequireAllReviewers,
+		teamName,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return storageErrors.ErrTeamNotFound
+	}
+
+	return nil
+}
+
 func (ts *TeamPostgresStorage) GetUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
 
 	rows, err := ts.db.QueryContext(ctx, `
@@ -88,3 +214,161 @@ func (ts *TeamPostgresStorage) GetUsersByTeam(ctx context.Context, teamName stri
 
 	return result, nil
 }
+
+// AdvanceRotation moves the team's round-robin cursor by step and returns
+// the position before the move. The upsert is a single statement, so
+// concurrent callers always receive distinct positions.
+func (ts *TeamPostgresStorage) AdvanceRotation(ctx context.Context, teamName string, step int) (int64, error) {
+	var position int64
+
+	err := ts.db.QueryRowContext(ctx, `
+		INSERT INTO team_rotations (team_name, position)
+		VALUES ($1, $2)
+		ON CONFLICT (team_name) DO UPDATE
+		SET position = team_rotations.position + EXCLUDED.position
+		RETURNING position - $2;`,
+		teamName,
+		step,
+	).Scan(&position)
+
+	return position, err
+}
+
+func (ts *TeamPostgresStorage) ListTeams(ctx context.Context) ([]models.TeamSummary, error) {
+	rows, err := ts.db.QueryContext(ctx, `
+		SELECT t.team_name,
+		       t.required_reviewers,
+		       COUNT(u.user_id),
+		       COUNT(u.user_id) FILTER (WHERE u.is_active)
+		FROM teams t
+		LEFT JOIN users u
+		    ON u.team_name = t.team_name
+		GROUP BY t.team_name, t.required_reviewers
+		ORDER BY t.team_name;`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []models.TeamSummary{}
+
+	for rows.Next() {
+		var t models.TeamSummary
+		if err := rows.Scan(&t.TeamName, &t.RequiredReviewers, &t.Members, &t.ActiveMembers); err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+// GetDependentTeams returns the teams that list teamName as a fallback.
+func (ts *TeamPostgresStorage) GetDependentTeams(ctx context.Context, teamName string) ([]string, error) {
+	rows, err := ts.db.QueryContext(ctx, `
+		SELECT team_name
+		FROM team_fallbacks
+		WHERE fallback_team_name = $1
+		ORDER BY team_name;`,
+		teamName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []string{}
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		result = append(result, name)
+	}
+
+	return result, rows.Err()
+}
+
+// RenameTeam moves the team and every row that references it to newName.
+// The foreign keys do not cascade, so the team is copied under the new name,
+// references are repointed and the old row is deleted.
+func (ts *TeamPostgresStorage) RenameTeam(ctx context.Context, teamName, newName string) error {
+	return inTx(ctx, ts.db, func(q querier) error {
+		res, err := q.ExecContext(ctx, `
+			INSERT INTO teams
+			(team_name, required_reviewers, min_approvals, block_on_changes_requested,
+			 forbid_author_sole_approver, require_all_reviewers)
+			SELECT $2, required_reviewers, min_approvals, block_on_changes_requested,
+			       forbid_author_sole_approver, require_all_reviewers
+			FROM teams
+			WHERE team_name = $1;`,
+			teamName,
+			newName,
+		)
+		if err != nil {
+			return err
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return storageErrors.ErrTeamNotFound
+		}
+
+		for _, query := range []string{
+			`UPDATE users SET team_name = $2 WHERE team_name = $1;`,
+			`UPDATE team_rotations SET team_name = $2 WHERE team_name = $1;`,
+			`UPDATE team_fallbacks SET team_name = $2 WHERE team_name = $1;`,
+			`UPDATE team_fallbacks SET fallback_team_name = $2 WHERE fallback_team_name = $1;`,
+		} {
+			if _, err := q.ExecContext(ctx, query, teamName, newName); err != nil {
+				return err
+			}
+		}
+
+		_, err = q.ExecContext(ctx, `
+			DELETE FROM teams
+			WHERE team_name = $1;`,
+			teamName,
+		)
+		return err
+	})
+}
+
+// DeleteTeam removes the team with its rotation and fallback list. Members
+// and teams falling back to it still reference it and make it fail.
+func (ts *TeamPostgresStorage) DeleteTeam(ctx context.Context, teamName string) error {
+	return inTx(ctx, ts.db, func(q querier) error {
+		for _, query := range []string{
+			`DELETE FROM team_rotations WHERE team_name = $1;`,
+			`DELETE FROM team_fallbacks WHERE team_name = $1;`,
+		} {
+			if _, err := q.ExecContext(ctx, query, teamName); err != nil {
+				return err
+			}
+		}
+
+		res, err := q.ExecContext(ctx, `
+			DELETE FROM teams
+			WHERE team_name = $1;`,
+			teamName,
+		)
+		if err != nil {
+			return err
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return storageErrors.ErrTeamNotFound
+		}
+
+		return nil
+	})
+}
@@ -4,13 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"sort"
 
 	"github.com/pacahar/pr-reviewer-assignment/internal/models"
 	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
 )
 
 type UserPostgresStorage struct {
-	db *sql.DB
+	db querier
 }
 
 func (us *UserPostgresStorage) CreateUser(ctx context.Context, userID, username, teamName string) error {
@@ -55,6 +56,40 @@ func (us *UserPostgresStorage) SetUserActiveStatus(ctx context.Context, userID s
 	return err
 }
 
+// SetTeamActiveStatus updates every member of the team in one statement and
+// returns the ids of the users whose status changed, ordered by id.
+func (us *UserPostgresStorage) SetTeamActiveStatus(ctx context.Context, teamName string, isActive bool) ([]string, error) {
+	rows, err := us.db.QueryContext(ctx, `
+		UPDATE users
+		SET is_active = $1
+		WHERE team_name = $2 AND is_active <> $1
+		RETURNING user_id;`,
+		isActive,
+		teamName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changed := []string{}
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		changed = append(changed, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(changed)
+
+	return changed, nil
+}
+
 func (us *UserPostgresStorage) SetUserTeam(ctx context.Context, userID, teamName string) error {
 	_, err := us.db.ExecContext(ctx, `
 		UPDATE users
@@ -92,3 +127,24 @@ func (us *UserPostgresStorage) GetActiveUsersByTeam(ctx context.Context, teamNam
 
 	return users, nil
 }
+
+func (us *UserPostgresStorage) DeleteUser(ctx context.Context, userID string) error {
+	res, err := us.db.ExecContext(ctx, `
+		DELETE FROM users
+		WHERE user_id = $1;`,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return storageErrors.ErrUserNotFound
+	}
+
+	return nil
+}
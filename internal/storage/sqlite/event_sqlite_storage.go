@@ -0,0 +1,133 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+)
+
+type EventSQLiteStorage struct {
+	db querier
+}
+
+func (es *EventSQLiteStorage) AddEvent(ctx context.Context, event models.AssignmentEvent) error {
+	_, err := es.db.ExecContext(ctx, `
+		INSERT INTO assignment_events
+		(event_type, pull_request_id, user_id, replacement_id, actor, reason, created_at)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7);`,
+		event.EventType,
+		event.PullRequestID,
+		event.UserID,
+		event.ReplacementID,
+		event.Actor,
+		event.Reason,
+		event.CreatedAt,
+	)
+	return err
+}
+
+// AddEvents inserts events in order with one statement per batch.
+func (es *EventSQLiteStorage) AddEvents(ctx context.Context, events []models.AssignmentEvent) error {
+	return inTx(ctx, es.db, func(q querier) error {
+		for start := 0; start < len(events); start += batchSize {
+			batch := events[start:min(start+batchSize, len(events))]
+
+			args := make([]any, 0, 7*len(batch))
+			for _, e := range batch {
+				args = append(args,
+					e.EventType,
+					nullString(e.PullRequestID),
+					nullString(e.UserID),
+					nullString(e.ReplacementID),
+					nullString(e.Actor),
+					nullString(e.Reason),
+					e.CreatedAt,
+				)
+			}
+
+			if _, err := q.ExecContext(ctx, `
+				INSERT INTO assignment_events
+				(event_type, pull_request_id, user_id, replacement_id, actor, reason, created_at)
+				VALUES `+placeholders(len(batch), 7)+`;`,
+				args...,
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func (es *EventSQLiteStorage) GetEventsByPR(ctx context.Context, prID string) ([]models.AssignmentEvent, error) {
+	return es.getEvents(ctx, `
+		SELECT event_id,
+		       event_type,
+		       COALESCE(pull_request_id, ''),
+		       COALESCE(user_id, ''),
+		       COALESCE(replacement_id, ''),
+		       COALESCE(actor, ''),
+		       COALESCE(reason, ''),
+		       created_at
+		FROM assignment_events
+		WHERE pull_request_id = $1
+		ORDER BY event_id;`,
+		prID,
+	)
+}
+
+// GetEventsByUser returns events about the user, including reassignments
+// that made them a reviewer.
+func (es *EventSQLiteStorage) GetEventsByUser(ctx context.Context, userID string) ([]models.AssignmentEvent, error) {
+	return es.getEvents(ctx, `
+		SELECT event_id,
+		       event_type,
+		       COALESCE(pull_request_id, ''),
+		       COALESCE(user_id, ''),
+		       COALESCE(replacement_id, ''),
+		       COALESCE(actor, ''),
+		       COALESCE(reason, ''),
+		       created_at
+		FROM assignment_events
+		WHERE user_id = $1 OR replacement_id = $1
+		ORDER BY event_id;`,
+		userID,
+	)
+}
+
+func (es *EventSQLiteStorage) getEvents(ctx context.Context, query string, args ...any) ([]models.AssignmentEvent, error) {
+	rows, err := es.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []models.AssignmentEvent{}
+
+	for rows.Next() {
+		var e models.AssignmentEvent
+
+		err := rows.Scan(
+			&e.EventID,
+			&e.EventType,
+			&e.PullRequestID,
+			&e.UserID,
+			&e.ReplacementID,
+			&e.Actor,
+			&e.Reason,
+			&e.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, e)
+	}
+
+	return result, rows.Err()
+}
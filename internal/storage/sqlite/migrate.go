@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// migrate applies every migration newer than the file's user_version, each
+// in its own transaction. Files are named NNNNNN_name.sql and only go up.
+func migrate(ctx context.Context, db *sql.DB) error {
+	const op = "storage.sqlite.migrate"
+
+	names, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	sort.Strings(names)
+
+	var current int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version;").Scan(&current); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, name := range names {
+		base := strings.TrimPrefix(name, "migrations/")
+		prefix, _, _ := strings.Cut(base, "_")
+
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return fmt.Errorf("%s: bad migration name %q", op, base)
+		}
+		if version <= current {
+			continue
+		}
+
+		body, err := migrations.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		err = inTx(ctx, db, func(q querier) error {
+			if _, err := q.ExecContext(ctx, string(body)); err != nil {
+				return err
+			}
+			_, err := q.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d;", version))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %s: %w", op, base, err)
+		}
+	}
+
+	return nil
+}
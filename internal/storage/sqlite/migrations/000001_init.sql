@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS teams (
+    team_name TEXT PRIMARY KEY,
+    required_reviewers INTEGER NOT NULL DEFAULT 2
+);
+
+CREATE TABLE IF NOT EXISTS team_rotations (
+    team_name TEXT PRIMARY KEY REFERENCES teams(team_name),
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS team_fallbacks (
+    team_name TEXT NOT NULL REFERENCES teams(team_name),
+    fallback_team_name TEXT NOT NULL REFERENCES teams(team_name),
+    priority INTEGER NOT NULL,
+    PRIMARY KEY (team_name, fallback_team_name)
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    user_id TEXT PRIMARY KEY,
+    username TEXT NOT NULL,
+    team_name TEXT NOT NULL REFERENCES teams(team_name),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE TABLE IF NOT EXISTS pull_requests (
+    pull_request_id TEXT PRIMARY KEY,
+    pull_request_name TEXT NOT NULL,
+    author_id TEXT NOT NULL REFERENCES users(user_id),
+    status TEXT NOT NULL CHECK (status IN ('DRAFT', 'OPEN', 'CLOSED', 'MERGED')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    merged_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS pr_reviewers (
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id),
+    reviewer_id TEXT NOT NULL REFERENCES users(user_id),
+    assignment_seed INTEGER,
+    PRIMARY KEY (pull_request_id, reviewer_id)
+);
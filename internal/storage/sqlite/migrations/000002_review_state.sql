@@ -0,0 +1,3 @@
+ALTER TABLE pr_reviewers ADD COLUMN review_state TEXT NOT NULL DEFAULT 'PENDING'
+    CHECK (review_state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'COMMENTED'));
+ALTER TABLE pr_reviewers ADD COLUMN reviewed_at TIMESTAMP;
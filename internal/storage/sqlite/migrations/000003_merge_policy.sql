@@ -0,0 +1,4 @@
+ALTER TABLE teams ADD COLUMN min_approvals INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE teams ADD COLUMN block_on_changes_requested BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE teams ADD COLUMN forbid_author_sole_approver BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE teams ADD COLUMN require_all_reviewers BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS users_team_name_idx ON users (team_name);
+CREATE INDEX IF NOT EXISTS pr_reviewers_reviewer_idx ON pr_reviewers (reviewer_id);
@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS pull_requests_created_at_idx ON pull_requests (created_at, pull_request_id);
+CREATE INDEX IF NOT EXISTS pull_requests_status_created_at_idx ON pull_requests (status, created_at, pull_request_id);
+CREATE INDEX IF NOT EXISTS pull_requests_author_created_at_idx ON pull_requests (author_id, created_at, pull_request_id);
+-- Serves sort=name only; the name filter is a substring LIKE and scans.
+CREATE INDEX IF NOT EXISTS pull_requests_name_idx ON pull_requests (pull_request_name, pull_request_id);
@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests ADD COLUMN author_review_state TEXT
+    CHECK (author_review_state IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED'));
+ALTER TABLE pull_requests ADD COLUMN author_reviewed_at TIMESTAMP;
@@ -0,0 +1,123 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+	_ "modernc.org/sqlite"
+)
+
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// NewSQLiteStorage opens the database file at path, creating it when missing
+// and bringing its schema up to date.
+func NewSQLiteStorage(path string) (*storage.Storage, error) {
+	const op = "storage.sqlite.NewSQLiteStorage"
+
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// SQLite allows a single writer. One connection serializes all access,
+	// so transactions never fail with SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(context.Background(), db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	st := newStorage(db, &SQLiteTransactor{db: db})
+	st.Closer = db
+
+	return st, nil
+}
+
+func newStorage(q querier, transactor storage.Transactor) *storage.Storage {
+	return &storage.Storage{
+		UserStorage:        &UserSQLiteStorage{db: q},
+		TeamStorage:        &TeamSQLiteStorage{db: q},
+		PullRequestStorage: &PullRequestSQLiteStorage{db: q},
+		EventStorage:       &EventSQLiteStorage{db: q},
+		StatsStorage:       &StatsSQLiteStorage{db: q},
+		Transactor:         transactor,
+	}
+}
+
+type SQLiteTransactor struct {
+	db *sql.DB
+}
+
+func (t *SQLiteTransactor) WithTx(ctx context.Context, fn func(st *storage.Storage) error) error {
+	const op = "storage.sqlite.WithTx"
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	txStorage := newStorage(tx, nil)
+	txStorage.Transactor = &txTransactor{st: txStorage}
+
+	if err := fn(txStorage); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// txTransactor is used by storages that already run inside a transaction.
+type txTransactor struct {
+	st *storage.Storage
+}
+
+func (t *txTransactor) WithTx(ctx context.Context, fn func(st *storage.Storage) error) error {
+	return fn(t.st)
+}
+
+// inTx runs fn in a transaction unless q already is one.
+func inTx(ctx context.Context, q querier, fn func(q querier) error) error {
+	db, ok := q.(*sql.DB)
+	if !ok {
+		return fn(q)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// batchSize bounds the rows of one multi-row statement, keeping it well under
+// the driver's parameter limit.
+const batchSize = 1000
+
+// placeholders returns rows comma-separated tuples of width positional
+// parameters: (?, ?), (?, ?), ... The driver resolves numbered parameters by
+// name, which gets quadratic for statements with thousands of them.
+func placeholders(rows, width int) string {
+	row := "(" + strings.Repeat("?, ", width-1) + "?)"
+
+	return strings.Repeat(row+", ", rows-1) + row
+}
@@ -0,0 +1,22 @@
+package sqlite_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage/sqlite"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage/storagetest"
+)
+
+func TestSQLiteStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) *storage.Storage {
+		st, err := sqlite.NewSQLiteStorage(filepath.Join(t.TempDir(), "test.db"))
+		if err != nil {
+			t.Fatalf("NewSQLiteStorage: %v", err)
+		}
+		t.Cleanup(func() { st.Close() })
+
+		return st
+	})
+}
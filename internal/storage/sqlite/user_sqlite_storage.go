@@ -0,0 +1,150 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
+)
+
+type UserSQLiteStorage struct {
+	db querier
+}
+
+func (us *UserSQLiteStorage) CreateUser(ctx context.Context, userID, username, teamName string) error {
+	_, err := us.db.ExecContext(ctx, `
+		INSERT INTO users (user_id, username, team_name)
+		VALUES ($1, $2, $3);`,
+		userID,
+		username,
+		teamName,
+	)
+	return err
+}
+
+func (us *UserSQLiteStorage) GetUserByID(ctx context.Context, userID string) (models.User, error) {
+	var user models.User
+
+	err := us.db.QueryRowContext(ctx, `
+		SELECT user_id, username, team_name, is_active
+		FROM users
+		WHERE user_id = $1;`,
+		userID,
+	).Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.User{}, storageErrors.ErrUserNotFound
+		}
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+func (us *UserSQLiteStorage) SetUserActiveStatus(ctx context.Context, userID string, isActive bool) error {
+	_, err := us.db.ExecContext(ctx, `
+		UPDATE users
+		SET is_active = $1
+		WHERE user_id = $2;`,
+		isActive,
+		userID,
+	)
+	return err
+}
+
+// SetTeamActiveStatus updates every member of the team in one statement and
+// returns the ids of the users whose status changed, ordered by id.
+func (us *UserSQLiteStorage) SetTeamActiveStatus(ctx context.Context, teamName string, isActive bool) ([]string, error) {
+	rows, err := us.db.QueryContext(ctx, `
+		UPDATE users
+		SET is_active = $1
+		WHERE team_name = $2 AND is_active <> $1
+		RETURNING user_id;`,
+		isActive,
+		teamName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changed := []string{}
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		changed = append(changed, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(changed)
+
+	return changed, nil
+}
+
+func (us *UserSQLiteStorage) SetUserTeam(ctx context.Context, userID, teamName string) error {
+	_, err := us.db.ExecContext(ctx, `
+		UPDATE users
+		SET team_name = $1
+		WHERE user_id = $2;
+	`,
+		teamName,
+		userID,
+	)
+	return err
+}
+
+func (us *UserSQLiteStorage) GetActiveUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
+	rows, err := us.db.QueryContext(ctx, `
+		SELECT user_id, username, team_name, is_active
+		FROM users
+		WHERE team_name = $1 AND is_active = TRUE;`,
+		teamName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+func (us *UserSQLiteStorage) DeleteUser(ctx context.Context, userID string) error {
+	res, err := us.db.ExecContext(ctx, `
+		DELETE FROM users
+		WHERE user_id = $1;`,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return storageErrors.ErrUserNotFound
+	}
+
+	return nil
+}
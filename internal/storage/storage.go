@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/pacahar/pr-reviewer-assignment/internal/models"
@@ -11,28 +12,86 @@ type Storage struct {
 	UserStorage        UserStorage
 	TeamStorage        TeamStorage
 	PullRequestStorage PullRequestStorage
+	EventStorage       EventStorage
+	StatsStorage       StatsStorage
+	Transactor         Transactor
+
+	// Closer releases the backend's connections. It is nil for backends that
+	// hold none.
+	Closer io.Closer
+}
+
+func (s *Storage) Close() error {
+	if s.Closer == nil {
+		return nil
+	}
+
+	return s.Closer.Close()
+}
+
+// Transactor runs fn against a Storage bound to a single transaction. The
+// transaction is committed when fn returns nil and rolled back otherwise.
+// Calling WithTx on a Storage that is already inside a transaction reuses it.
+type Transactor interface {
+	WithTx(ctx context.Context, fn func(st *Storage) error) error
+}
+
+func (s *Storage) WithTx(ctx context.Context, fn func(st *Storage) error) error {
+	return s.Transactor.WithTx(ctx, fn)
 }
 
 type UserStorage interface {
 	CreateUser(ctx context.Context, userID, username, teamName string) error
 	GetUserByID(ctx context.Context, userID string) (models.User, error)
 	SetUserActiveStatus(ctx context.Context, userID string, isActive bool) error
+	SetTeamActiveStatus(ctx context.Context, teamName string, isActive bool) ([]string, error)
 	SetUserTeam(ctx context.Context, userID, teamName string) error
 	GetActiveUsersByTeam(ctx context.Context, teamName string) ([]models.User, error)
+	DeleteUser(ctx context.Context, userID string) error
 }
 
 type TeamStorage interface {
-	CreateTeam(ctx context.Context, teamName string) error
+	CreateTeam(ctx context.Context, teamName string, requiredReviewers int) error
 	GetTeamByName(ctx context.Context, teamName string) (models.Team, error)
+	SetRequiredReviewers(ctx context.Context, teamName string, requiredReviewers int) error
+	SetFallbackTeams(ctx context.Context, teamName string, fallbackTeams []string) error
+	SetMergePolicy(ctx context.Context, teamName string, policy models.MergePolicy) error
 	GetUsersByTeam(ctx context.Context, teamName string) ([]models.User, error)
+	AdvanceRotation(ctx context.Context, teamName string, step int) (int64, error)
+	ListTeams(ctx context.Context) ([]models.TeamSummary, error)
+	GetDependentTeams(ctx context.Context, teamName string) ([]string, error)
+	RenameTeam(ctx context.Context, teamName, newName string) error
+	DeleteTeam(ctx context.Context, teamName string) error
 }
 
 type PullRequestStorage interface {
-	CreatePullRequest(ctx context.Context, prID, prName, authorID string) error
+	CreatePullRequest(ctx context.Context, prID, prName, authorID string, status models.PullRequestStatus) error
 	GetPullRequestByID(ctx context.Context, prID string) (models.PullRequest, error)
-	SetPullRequestStatus(ctx context.Context, prID, status string, time time.Time) error
-	AddReviewer(ctx context.Context, prID, userID string) error
+	LockPullRequest(ctx context.Context, prID string) error
+	SetPullRequestStatus(ctx context.Context, prID string, status models.PullRequestStatus, time time.Time) error
+	AddReviewer(ctx context.Context, prID, userID string, seed *int64) error
 	RemoveReviewer(ctx context.Context, prID, userID string) error
+	ReplaceReviewers(ctx context.Context, replacements []models.ReviewerReplacement) error
 	GetReviewersByPR(ctx context.Context, prID string) ([]string, error)
-	GetPullRequestsByReviewer(ctx context.Context, reviewerID string) ([]models.PullRequestShort, error)
+	SetReviewState(ctx context.Context, prID, reviewerID string, state models.ReviewState, time time.Time) error
+	SetAuthorReviewState(ctx context.Context, prID string, state models.ReviewState, time time.Time) error
+	GetReviewsByPR(ctx context.Context, prID string) ([]models.Review, error)
+	ListPullRequests(ctx context.Context, query models.PullRequestQuery) ([]models.PullRequestShort, error)
+	GetPullRequestsByReviewer(ctx context.Context, reviewerID string, query models.PullRequestQuery) ([]models.PullRequestShort, error)
+	GetOpenReviewCountsByTeam(ctx context.Context, teamName string) (map[string]int, error)
+	GetOpenReviewsByTeam(ctx context.Context, teamName string) ([]models.ReviewAssignment, error)
+	CountPullRequestsByUser(ctx context.Context, userID string) (int, error)
+}
+
+// EventStorage is append-only: events are never updated or deleted.
+type EventStorage interface {
+	AddEvent(ctx context.Context, event models.AssignmentEvent) error
+	AddEvents(ctx context.Context, events []models.AssignmentEvent) error
+	GetEventsByPR(ctx context.Context, prID string) ([]models.AssignmentEvent, error)
+	GetEventsByUser(ctx context.Context, userID string) ([]models.AssignmentEvent, error)
+}
+
+type StatsStorage interface {
+	GetUserStats(ctx context.Context, filter models.StatsFilter) ([]models.UserStats, error)
+	GetReviewLoad(ctx context.Context, teamName string, since time.Time) (models.LoadDistribution, error)
 }
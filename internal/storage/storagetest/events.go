@@ -0,0 +1,144 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+)
+
+func runEventTests(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+
+	t.Run("GetEventsEmpty", func(t *testing.T) {
+		st := newStorage(t)
+
+		events, err := st.EventStorage.GetEventsByPR(ctx, "missing")
+		if err != nil {
+			t.Fatalf("GetEventsByPR: %v", err)
+		}
+		if events == nil || len(events) != 0 {
+			t.Fatalf("GetEventsByPR: got %v, want empty slice", events)
+		}
+	})
+
+	t.Run("AddEvent", func(t *testing.T) {
+		st := newStorage(t)
+
+		at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		added := []models.AssignmentEvent{
+			{EventType: models.EventAssign, PullRequestID: "pr1", UserID: "r1", Reason: "pull request created", CreatedAt: at},
+			{EventType: models.EventReassign, PullRequestID: "pr1", UserID: "r1", ReplacementID: "r2", Actor: "alice", CreatedAt: at},
+			{EventType: models.EventDeactivate, UserID: "r1", CreatedAt: at},
+			{EventType: models.EventAssign, PullRequestID: "pr2", UserID: "r3", CreatedAt: at},
+		}
+		for _, e := range added {
+			if err := st.EventStorage.AddEvent(ctx, e); err != nil {
+				t.Fatalf("AddEvent: %v", err)
+			}
+		}
+
+		byPR, err := st.EventStorage.GetEventsByPR(ctx, "pr1")
+		if err != nil {
+			t.Fatalf("GetEventsByPR: %v", err)
+		}
+		assertEvents(t, "GetEventsByPR", byPR, added[0], added[1])
+
+		byUser, err := st.EventStorage.GetEventsByUser(ctx, "r1")
+		if err != nil {
+			t.Fatalf("GetEventsByUser: %v", err)
+		}
+		assertEvents(t, "GetEventsByUser", byUser, added[0], added[1], added[2])
+
+		byReplacement, err := st.EventStorage.GetEventsByUser(ctx, "r2")
+		if err != nil {
+			t.Fatalf("GetEventsByUser: %v", err)
+		}
+		assertEvents(t, "GetEventsByUser for replacement", byReplacement, added[1])
+	})
+
+	t.Run("AddEvents", func(t *testing.T) {
+		st := newStorage(t)
+
+		if err := st.EventStorage.AddEvents(ctx, nil); err != nil {
+			t.Fatalf("AddEvents with no events: %v", err)
+		}
+
+		// More events than fit into one statement.
+		at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		added := make([]models.AssignmentEvent, 0, 2500)
+		for i := 0; i < cap(added); i++ {
+			added = append(added, models.AssignmentEvent{
+				EventType: models.EventDeactivate,
+				UserID:    "u1",
+				Reason:    fmt.Sprintf("event %d", i),
+				CreatedAt: at,
+			})
+		}
+		added[0].Actor = "alice"
+
+		if err := st.EventStorage.AddEvents(ctx, added); err != nil {
+			t.Fatalf("AddEvents: %v", err)
+		}
+
+		events, err := st.EventStorage.GetEventsByUser(ctx, "u1")
+		if err != nil {
+			t.Fatalf("GetEventsByUser: %v", err)
+		}
+		assertEvents(t, "GetEventsByUser", events, added...)
+	})
+
+	t.Run("AddEventRollback", func(t *testing.T) {
+		st := newStorage(t)
+		errInjected := errors.New("injected failure")
+
+		err := st.WithTx(ctx, func(tx *storage.Storage) error {
+			if err := tx.EventStorage.AddEvent(ctx, models.AssignmentEvent{
+				EventType: models.EventActivate,
+				UserID:    "u1",
+				CreatedAt: time.Now().UTC(),
+			}); err != nil {
+				t.Fatalf("AddEvent: %v", err)
+			}
+			return errInjected
+		})
+		if !errors.Is(err, errInjected) {
+			t.Fatalf("WithTx: got %v, want injected error", err)
+		}
+
+		events, err := st.EventStorage.GetEventsByUser(ctx, "u1")
+		if err != nil {
+			t.Fatalf("GetEventsByUser: %v", err)
+		}
+		if len(events) != 0 {
+			t.Fatalf("GetEventsByUser after rollback: got %v, want none", events)
+		}
+	})
+}
+
+// assertEvents compares events ignoring EventID, which must only increase.
+func assertEvents(t *testing.T, name string, got []models.AssignmentEvent, want ...models.AssignmentEvent) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %d events, want %d: %+v", name, len(got), len(want), got)
+	}
+
+	for i := range got {
+		if i > 0 && got[i].EventID <= got[i-1].EventID {
+			t.Fatalf("%s: event ids not increasing: %+v", name, got)
+		}
+
+		g := got[i]
+		g.EventID = 0
+		g.CreatedAt = g.CreatedAt.UTC()
+		if !reflect.DeepEqual(g, want[i]) {
+			t.Fatalf("%s: event %d: got %+v, want %+v", name, i, g, want[i])
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package storagetest
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage/postgres"
+)
+
+const PostgresDSNEnv = "STORAGETEST_POSTGRES_DSN"
+
+// Postgres is a Factory for a migrated database reachable at the DSN in
+// STORAGETEST_POSTGRES_DSN, e.g. the docker-compose one:
+//
+//	docker compose up -d db && docker compose run --rm migrate up
+//	STORAGETEST_POSTGRES_DSN="host=localhost port=5432 user=app password=app dbname=assignment sslmode=disable" go test ./...
+//
+// Every table is truncated before the storage is returned, and the storage is
+// closed when the test ends. Tests are skipped when the variable is unset.
+func Postgres(t *testing.T) *storage.Storage {
+	t.Helper()
+
+	dsn := os.Getenv(PostgresDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", PostgresDSNEnv)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open postgres: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		TRUNCATE teams, team_rotations, team_fallbacks, users, pull_requests, pr_reviewers, assignment_events
+		RESTART IDENTITY CASCADE;`,
+	); err != nil {
+		t.Fatalf("truncate tables: %v", err)
+	}
+
+	st, err := postgres.NewPostgresStorage(dsn, false)
+	if err != nil {
+		t.Fatalf("NewPostgresStorage: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+
+	return st
+}
@@ -0,0 +1,677 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/assignment"
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
+)
+
+func runPullRequestTests(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+
+	t.Run("GetPullRequestByIDNotFound", func(t *testing.T) {
+		st := newStorage(t)
+
+		_, err := st.PullRequestStorage.GetPullRequestByID(ctx, "missing")
+		if !errors.Is(err, storageErrors.ErrPRNotFound) {
+			t.Fatalf("got %v, want ErrPRNotFound", err)
+		}
+	})
+
+	t.Run("CreatePullRequest", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateUser(t, st, "author", "backend")
+
+		if err := st.PullRequestStorage.CreatePullRequest(ctx, "pr1", "Add feature", "author", models.StatusOpen); err != nil {
+			t.Fatalf("CreatePullRequest: %v", err)
+		}
+
+		pr, err := st.PullRequestStorage.GetPullRequestByID(ctx, "pr1")
+		if err != nil {
+			t.Fatalf("GetPullRequestByID: %v", err)
+		}
+		if pr.PullRequestID != "pr1" || pr.PullRequestName != "Add feature" || pr.AuthorID != "author" {
+			t.Fatalf("GetPullRequestByID: got %+v", pr)
+		}
+		if pr.Status != models.StatusOpen || pr.CreatedAt == nil || pr.MergedAt != nil {
+			t.Fatalf("GetPullRequestByID: got %+v", pr)
+		}
+		if len(pr.AssignedReviewers) != 0 {
+			t.Fatalf("AssignedReviewers: got %v, want none", pr.AssignedReviewers)
+		}
+	})
+
+	t.Run("CreatePullRequestDraft", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateUser(t, st, "author", "backend")
+
+		if err := st.PullRequestStorage.CreatePullRequest(ctx, "pr1", "WIP", "author", models.StatusDraft); err != nil {
+			t.Fatalf("CreatePullRequest: %v", err)
+		}
+
+		pr, err := st.PullRequestStorage.GetPullRequestByID(ctx, "pr1")
+		if err != nil {
+			t.Fatalf("GetPullRequestByID: %v", err)
+		}
+		if pr.Status != models.StatusDraft {
+			t.Fatalf("Status: got %q, want DRAFT", pr.Status)
+		}
+	})
+
+	t.Run("CreatePullRequestDuplicate", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateUser(t, st, "author", "backend")
+		mustCreatePullRequest(t, st, "pr1", "author")
+
+		if err := st.PullRequestStorage.CreatePullRequest(ctx, "pr1", "Again", "author", models.StatusOpen); err == nil {
+			t.Fatal("CreatePullRequest with duplicate id: got nil error")
+		}
+	})
+
+	t.Run("CreatePullRequestUnknownAuthor", func(t *testing.T) {
+		st := newStorage(t)
+
+		if err := st.PullRequestStorage.CreatePullRequest(ctx, "pr1", "Add feature", "missing", models.StatusOpen); err == nil {
+			t.Fatal("CreatePullRequest with unknown author: got nil error")
+		}
+	})
+
+	t.Run("LockPullRequest", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateUser(t, st, "author", "backend")
+		mustCreatePullRequest(t, st, "pr1", "author")
+
+		err := st.WithTx(ctx, func(tx *storage.Storage) error {
+			if err := tx.PullRequestStorage.LockPullRequest(ctx, "pr1"); err != nil {
+				return err
+			}
+
+			err := tx.PullRequestStorage.LockPullRequest(ctx, "missing")
+			if !errors.Is(err, storageErrors.ErrPRNotFound) {
+				t.Errorf("LockPullRequest on missing PR: got %v, want ErrPRNotFound", err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("LockPullRequest: %v", err)
+		}
+	})
+
+	t.Run("SetPullRequestStatus", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateUser(t, st, "author", "backend")
+		mustCreatePullRequest(t, st, "pr1", "author")
+
+		mergedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		if err := st.PullRequestStorage.SetPullRequestStatus(ctx, "pr1", models.StatusMerged, mergedAt); err != nil {
+			t.Fatalf("SetPullRequestStatus: %v", err)
+		}
+
+		pr, err := st.PullRequestStorage.GetPullRequestByID(ctx, "pr1")
+		if err != nil {
+			t.Fatalf("GetPullRequestByID: %v", err)
+		}
+		if pr.Status != models.StatusMerged {
+			t.Fatalf("Status: got %q, want MERGED", pr.Status)
+		}
+		if pr.MergedAt == nil || !pr.MergedAt.Equal(mergedAt) {
+			t.Fatalf("MergedAt: got %v, want %v", pr.MergedAt, mergedAt)
+		}
+
+		if err := st.PullRequestStorage.SetPullRequestStatus(ctx, "pr1", models.StatusOpen, time.Now().UTC()); err != nil {
+			t.Fatalf("SetPullRequestStatus: %v", err)
+		}
+
+		pr, err = st.PullRequestStorage.GetPullRequestByID(ctx, "pr1")
+		if err != nil {
+			t.Fatalf("GetPullRequestByID: %v", err)
+		}
+		if pr.Status != models.StatusOpen || pr.MergedAt != nil {
+			t.Fatalf("non-merged status must clear merged_at: got %+v", pr)
+		}
+	})
+
+	t.Run("AddReviewer", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateUser(t, st, "author", "backend")
+		mustCreateUser(t, st, "r1", "backend")
+		mustCreateUser(t, st, "r2", "backend")
+		mustCreatePullRequest(t, st, "pr1", "author")
+
+		seed := int64(42)
+		if err := st.PullRequestStorage.AddReviewer(ctx, "pr1", "r1", &seed); err != nil {
+			t.Fatalf("AddReviewer: %v", err)
+		}
+		if err := st.PullRequestStorage.AddReviewer(ctx, "pr1", "r2", nil); err != nil {
+			t.Fatalf("AddReviewer: %v", err)
+		}
+		if err := st.PullRequestStorage.AddReviewer(ctx, "pr1", "r1", nil); err != nil {
+			t.Fatalf("AddReviewer for an assigned reviewer must be a no-op: %v", err)
+		}
+
+		reviewers, err := st.PullRequestStorage.GetReviewersByPR(ctx, "pr1")
+		if err != nil {
+			t.Fatalf("GetReviewersByPR: %v", err)
+		}
+		if got, want := sortedStrings(reviewers), []string{"r1", "r2"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("GetReviewersByPR: got %v, want %v", got, want)
+		}
+
+		pr, err := st.PullRequestStorage.GetPullRequestByID(ctx, "pr1")
+		if err != nil {
+			t.Fatalf("GetPullRequestByID: %v", err)
+		}
+		if len(pr.Reviews) != 2 || pr.Reviews[0].AssignmentSeed == nil || *pr.Reviews[0].AssignmentSeed != seed || pr.Reviews[1].AssignmentSeed != nil {
+			t.Fatalf("GetPullRequestByID reviews: got %+v, want seed %d for r1 only", pr.Reviews, seed)
+		}
+
+		if err := st.PullRequestStorage.AddReviewer(ctx, "pr1", "missing", nil); err == nil {
+			t.Fatal("AddReviewer with unknown user: got nil error")
+		}
+		if err := st.PullRequestStorage.AddReviewer(ctx, "missing", "r1", nil); err == nil {
+			t.Fatal("AddReviewer with unknown PR: got nil error")
+		}
+	})
+
+	t.Run("AssignmentSeedReplay", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateTeam(t, st, "frontend")
+		for _, id := range []string{"u4", "u2", "u5", "u1", "u3"} {
+			mustCreateUser(t, st, id, "backend")
+		}
+		mustCreateUser(t, st, "author", "frontend")
+		mustCreatePullRequest(t, st, "pr1", "author")
+
+		candidates, err := st.UserStorage.GetActiveUsersByTeam(ctx, "backend")
+		if err != nil {
+			t.Fatalf("GetActiveUsersByTeam: %v", err)
+		}
+
+		selection, err := assignment.NewRandomSelector(func() int64 { return 7 }).Select(ctx, st, assignment.Request{
+			TeamName:   "backend",
+			Candidates: candidates,
+			Count:      2,
+		})
+		if err != nil {
+			t.Fatalf("Select: %v", err)
+		}
+
+		picked := map[string]bool{}
+		for _, u := range selection.Reviewers {
+			picked[u.UserID] = true
+			if err := st.PullRequestStorage.AddReviewer(ctx, "pr1", u.UserID, selection.Seed); err != nil {
+				t.Fatalf("AddReviewer(%q): %v", u.UserID, err)
+			}
+		}
+
+		// Recreating and toggling users moves their rows, so the listing
+		// order differs from the one the selection saw.
+		for _, u := range candidates {
+			if picked[u.UserID] {
+				if err := st.UserStorage.SetUserActiveStatus(ctx, u.UserID, false); err != nil {
+					t.Fatalf("SetUserActiveStatus: %v", err)
+				}
+				if err := st.UserStorage.SetUserActiveStatus(ctx, u.UserID, true); err != nil {
+					t.Fatalf("SetUserActiveStatus: %v", err)
+				}
+				continue
+			}
+
+			if err := st.UserStorage.DeleteUser(ctx, u.UserID); err != nil {
+				t.Fatalf("DeleteUser(%q): %v", u.UserID, err)
+			}
+			mustCreateUser(t, st, u.UserID, "backend")
+		}
+
+		pr, err := st.PullRequestStorage.GetPullRequestByID(ctx, "pr1")
+		if err != nil {
+			t.Fatalf("GetPullRequestByID: %v", err)
+		}
+
+		candidates, err = st.UserStorage.GetActiveUsersByTeam(ctx, "backend")
+		if err != nil {
+			t.Fatalf("GetActiveUsersByTeam: %v", err)
+		}
+
+		var stored []string
+		for _, r := range pr.Reviews {
+			if r.AssignmentSeed == nil {
+				t.Fatalf("review %+v has no seed", r)
+			}
+			stored = append(stored, r.ReviewerID)
+		}
+
+		var replayed []string
+		for _, u := range assignment.Shuffle(candidates, *pr.Reviews[0].AssignmentSeed)[:2] {
+			replayed = append(replayed, u.UserID)
+		}
+
+		if got, want := sortedStrings(replayed), sortedStrings(stored); !reflect.DeepEqual(got, want) {
+			t.Fatalf("replayed reviewers %v, stored %v", got, want)
+		}
+	})
+
+	t.Run("RemoveReviewer", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateUser(t, st, "author", "backend")
+		mustCreateUser(t, st, "r1", "backend")
+		mustCreateUser(t, st, "r2", "backend")
+		mustCreatePullRequest(t, st, "pr1", "author", "r1", "r2")
+
+		if err := st.PullRequestStorage.RemoveReviewer(ctx, "pr1", "r1"); err != nil {
+			t.Fatalf("RemoveReviewer: %v", err)
+		}
+		if err := st.PullRequestStorage.RemoveReviewer(ctx, "pr1", "r1"); err != nil {
+			t.Fatalf("RemoveReviewer for an unassigned reviewer: %v", err)
+		}
+
+		reviewers, err := st.PullRequestStorage.GetReviewersByPR(ctx, "pr1")
+		if err != nil {
+			t.Fatalf("GetReviewersByPR: %v", err)
+		}
+		if want := []string{"r2"}; !reflect.DeepEqual(reviewers, want) {
+			t.Fatalf("GetReviewersByPR: got %v, want %v", reviewers, want)
+		}
+	})
+
+	t.Run("ReplaceReviewers", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateUser(t, st, "author", "backend")
+		mustCreateUser(t, st, "r1", "backend")
+		mustCreateUser(t, st, "r2", "backend")
+		mustCreateUser(t, st, "r3", "backend")
+		mustCreatePullRequest(t, st, "pr1", "author", "r1", "r2")
+		mustCreatePullRequest(t, st, "pr2", "author", "r1")
+
+		if err := st.PullRequestStorage.ReplaceReviewers(ctx, nil); err != nil {
+			t.Fatalf("ReplaceReviewers with no replacements: %v", err)
+		}
+
+		seed := int64(7)
+		err := st.PullRequestStorage.ReplaceReviewers(ctx, []models.ReviewerReplacement{
+			{PullRequestID: "pr1", OldReviewerID: "r1", NewReviewerID: "r3", Seed: &seed},
+			{PullRequestID: "pr2", OldReviewerID: "r1", NewReviewerID: "r2"},
+		})
+		if err != nil {
+			t.Fatalf("ReplaceReviewers: %v", err)
+		}
+
+		pr1, err := st.PullRequestStorage.GetPullRequestByID(ctx, "pr1")
+		if err != nil {
+			t.Fatalf("GetPullRequestByID: %v", err)
+		}
+		if got, want := sortedStrings(pr1.AssignedReviewers), []string{"r2", "r3"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("pr1 reviewers: got %v, want %v", got, want)
+		}
+		if r3 := pr1.Reviews[1]; r3.ReviewerID != "r3" || r3.AssignmentSeed == nil || *r3.AssignmentSeed != seed {
+			t.Fatalf("pr1 reviews: got %+v, want seed %d for r3", pr1.Reviews, seed)
+		}
+
+		pr2, err := st.PullRequestStorage.GetReviewersByPR(ctx, "pr2")
+		if err != nil {
+			t.Fatalf("GetReviewersByPR: %v", err)
+		}
+		if want := []string{"r2"}; !reflect.DeepEqual(pr2, want) {
+			t.Fatalf("pr2 reviewers: got %v, want %v", pr2, want)
+		}
+
+		err = st.PullRequestStorage.ReplaceReviewers(ctx, []models.ReviewerReplacement{
+			{PullRequestID: "pr2", OldReviewerID: "r2", NewReviewerID: "missing"},
+		})
+		if err == nil {
+			t.Fatal("ReplaceReviewers with unknown user: got nil error")
+		}
+
+		pr2, err = st.PullRequestStorage.GetReviewersByPR(ctx, "pr2")
+		if err != nil {
+			t.Fatalf("GetReviewersByPR: %v", err)
+		}
+		if want := []string{"r2"}; !reflect.DeepEqual(pr2, want) {
+			t.Fatalf("failed ReplaceReviewers must change nothing: got %v, want %v", pr2, want)
+		}
+	})
+
+	t.Run("GetReviewersByPREmpty", func(t *testing.T) {
+		st := newStorage(t)
+
+		reviewers, err := st.PullRequestStorage.GetReviewersByPR(ctx, "missing")
+		if err != nil {
+			t.Fatalf("GetReviewersByPR: %v", err)
+		}
+		if len(reviewers) != 0 {
+			t.Fatalf("GetReviewersByPR: got %v, want none", reviewers)
+		}
+	})
+
+	t.Run("SetReviewState", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateUser(t, st, "author", "backend")
+		mustCreateUser(t, st, "r1", "backend")
+		mustCreateUser(t, st, "r2", "backend")
+		mustCreatePullRequest(t, st, "pr1", "author", "r1", "r2")
+
+		reviewedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		if err := st.PullRequestStorage.SetReviewState(ctx, "pr1", "r1", models.ReviewApproved, reviewedAt); err != nil {
+			t.Fatalf("SetReviewState: %v", err)
+		}
+
+		err := st.PullRequestStorage.SetReviewState(ctx, "pr1", "author", models.ReviewApproved, reviewedAt)
+		if !errors.Is(err, storageErrors.ErrReviewerNotAssigned) {
+			t.Fatalf("SetReviewState for unassigned user: got %v, want ErrReviewerNotAssigned", err)
+		}
+
+		reviews, err := st.PullRequestStorage.GetReviewsByPR(ctx, "pr1")
+		if err != nil {
+			t.Fatalf("GetReviewsByPR: %v", err)
+		}
+		if len(reviews) != 2 || reviews[0].ReviewerID != "r1" || reviews[1].ReviewerID != "r2" {
+			t.Fatalf("GetReviewsByPR: got %+v", reviews)
+		}
+		if reviews[0].State != models.ReviewApproved || reviews[0].SubmittedAt == nil || !reviews[0].SubmittedAt.Equal(reviewedAt) {
+			t.Fatalf("GetReviewsByPR: got %+v for r1", reviews[0])
+		}
+		if reviews[1].State != models.ReviewPending || reviews[1].SubmittedAt != nil {
+			t.Fatalf("GetReviewsByPR: got %+v for r2", reviews[1])
+		}
+
+		pr, err := st.PullRequestStorage.GetPullRequestByID(ctx, "pr1")
+		if err != nil {
+			t.Fatalf("GetPullRequestByID: %v", err)
+		}
+		if !reflect.DeepEqual(pr.Reviews, reviews) {
+			t.Fatalf("GetPullRequestByID reviews: got %+v, want %+v", pr.Reviews, reviews)
+		}
+
+		prs, err := st.PullRequestStorage.GetPullRequestsByReviewer(ctx, "r1", models.PullRequestQuery{})
+		if err != nil {
+			t.Fatalf("GetPullRequestsByReviewer: %v", err)
+		}
+		if len(prs) != 1 || prs[0].ReviewState != models.ReviewApproved {
+			t.Fatalf("GetPullRequestsByReviewer: got %+v", prs)
+		}
+	})
+
+	t.Run("SetAuthorReviewState", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateUser(t, st, "author", "backend")
+		mustCreateUser(t, st, "r1", "backend")
+		mustCreatePullRequest(t, st, "pr1", "author", "r1")
+
+		reviewedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		if err := st.PullRequestStorage.SetAuthorReviewState(ctx, "pr1", models.ReviewApproved, reviewedAt); err != nil {
+			t.Fatalf("SetAuthorReviewState: %v", err)
+		}
+
+		err := st.PullRequestStorage.SetAuthorReviewState(ctx, "missing", models.ReviewApproved, reviewedAt)
+		if !errors.Is(err, storageErrors.ErrPRNotFound) {
+			t.Fatalf("SetAuthorReviewState for unknown PR: got %v, want ErrPRNotFound", err)
+		}
+
+		pr, err := st.PullRequestStorage.GetPullRequestByID(ctx, "pr1")
+		if err != nil {
+			t.Fatalf("GetPullRequestByID: %v", err)
+		}
+		if len(pr.Reviews) != 2 || pr.Reviews[0].ReviewerID != "author" || pr.Reviews[1].ReviewerID != "r1" {
+			t.Fatalf("GetPullRequestByID reviews: got %+v", pr.Reviews)
+		}
+		author := pr.Reviews[0]
+		if author.State != models.ReviewApproved || author.AssignmentSeed != nil || author.SubmittedAt == nil || !author.SubmittedAt.Equal(reviewedAt) {
+			t.Fatalf("GetPullRequestByID reviews: got %+v for the author", author)
+		}
+		if want := []string{"r1"}; !reflect.DeepEqual(pr.AssignedReviewers, want) {
+			t.Fatalf("author must not become a reviewer: got %v", pr.AssignedReviewers)
+		}
+	})
+
+	t.Run("GetPullRequestsByReviewer", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateUser(t, st, "author", "backend")
+		mustCreateUser(t, st, "r1", "backend")
+		mustCreateUser(t, st, "r2", "backend")
+		mustCreatePullRequest(t, st, "pr1", "author", "r1")
+		mustCreatePullRequest(t, st, "pr2", "author", "r1", "r2")
+		mustCreatePullRequest(t, st, "pr3", "author", "r2")
+
+		if err := st.PullRequestStorage.SetPullRequestStatus(ctx, "pr2", models.StatusMerged, time.Now().UTC()); err != nil {
+			t.Fatalf("SetPullRequestStatus: %v", err)
+		}
+
+		prs, err := st.PullRequestStorage.GetPullRequestsByReviewer(ctx, "r1", models.PullRequestQuery{})
+		if err != nil {
+			t.Fatalf("GetPullRequestsByReviewer: %v", err)
+		}
+
+		statuses := map[string]models.PullRequestStatus{}
+		for _, pr := range prs {
+			if pr.AuthorID != "author" || pr.PullRequestName != pr.PullRequestID {
+				t.Fatalf("GetPullRequestsByReviewer: got %+v", pr)
+			}
+			statuses[pr.PullRequestID] = pr.Status
+		}
+		if want := map[string]models.PullRequestStatus{"pr1": models.StatusOpen, "pr2": models.StatusMerged}; !reflect.DeepEqual(statuses, want) {
+			t.Fatalf("GetPullRequestsByReviewer: got %v, want %v", statuses, want)
+		}
+	})
+
+	t.Run("GetPullRequestsByReviewerPage", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateUser(t, st, "author", "backend")
+		mustCreateUser(t, st, "other", "backend")
+		mustCreateUser(t, st, "r1", "backend")
+		mustCreatePullRequest(t, st, "pr1", "author", "r1")
+		mustCreatePullRequest(t, st, "pr2", "other", "r1")
+		mustCreatePullRequest(t, st, "pr3", "author", "r1")
+		mustCreatePullRequest(t, st, "pr4", "author")
+		mustCreatePullRequest(t, st, "pr5", "author", "r1")
+
+		if err := st.PullRequestStorage.SetPullRequestStatus(ctx, "pr3", models.StatusMerged, time.Now().UTC()); err != nil {
+			t.Fatalf("SetPullRequestStatus: %v", err)
+		}
+
+		list := func(query models.PullRequestQuery) ([]string, []models.PullRequestShort) {
+			t.Helper()
+
+			prs, err := st.PullRequestStorage.GetPullRequestsByReviewer(ctx, "r1", query)
+			if err != nil {
+				t.Fatalf("GetPullRequestsByReviewer(%+v): %v", query, err)
+			}
+
+			ids := []string{}
+			for _, pr := range prs {
+				ids = append(ids, pr.PullRequestID)
+			}
+			return ids, prs
+		}
+
+		var pages []string
+		query := models.PullRequestQuery{Limit: 2}
+		for {
+			ids, prs := list(query)
+			pages = append(pages, strings.Join(ids, ","))
+			if len(prs) < query.Limit {
+				break
+			}
+			last := prs[len(prs)-1]
+			query.After = &models.PullRequestCursor{CreatedAt: last.CreatedAt, PullRequestID: last.PullRequestID}
+		}
+		if want := []string{"pr1,pr2", "pr3,pr5", ""}; !reflect.DeepEqual(pages, want) {
+			t.Fatalf("pages: got %q, want %q", pages, want)
+		}
+
+		ids, _ := list(models.PullRequestQuery{PullRequestFilter: models.PullRequestFilter{
+			Statuses: []models.PullRequestStatus{models.StatusOpen},
+			AuthorID: "author",
+		}})
+		if want := []string{"pr1", "pr5"}; !reflect.DeepEqual(ids, want) {
+			t.Fatalf("filtered: got %v, want %v", ids, want)
+		}
+
+		past := time.Now().UTC().Add(-time.Hour)
+		future := time.Now().UTC().Add(time.Hour)
+
+		ids, _ = list(models.PullRequestQuery{PullRequestFilter: models.PullRequestFilter{CreatedFrom: &past, CreatedTo: &future}})
+		if len(ids) != 4 {
+			t.Fatalf("date range: got %v, want all four", ids)
+		}
+
+		ids, _ = list(models.PullRequestQuery{PullRequestFilter: models.PullRequestFilter{CreatedTo: &past}})
+		if len(ids) != 0 {
+			t.Fatalf("date range in the past: got %v, want none", ids)
+		}
+	})
+
+	t.Run("ListPullRequests", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateTeam(t, st, "frontend")
+		mustCreateUser(t, st, "b1", "backend")
+		mustCreateUser(t, st, "f1", "frontend")
+		mustCreateUser(t, st, "r1", "backend")
+
+		ctx := context.Background()
+		for _, pr := range []struct{ id, name, author string }{
+			{"pr1", "Fix login", "b1"},
+			{"pr2", "Add 100% coverage", "f1"},
+			{"pr3", "fix_layout", "f1"},
+			{"pr4", "Bump deps", "b1"},
+		} {
+			if err := st.PullRequestStorage.CreatePullRequest(ctx, pr.id, pr.name, pr.author, models.StatusOpen); err != nil {
+				t.Fatalf("CreatePullRequest(%q): %v", pr.id, err)
+			}
+		}
+		if err := st.PullRequestStorage.AddReviewer(ctx, "pr3", "r1", nil); err != nil {
+			t.Fatalf("AddReviewer: %v", err)
+		}
+
+		list := func(query models.PullRequestQuery) []string {
+			t.Helper()
+
+			prs, err := st.PullRequestStorage.ListPullRequests(ctx, query)
+			if err != nil {
+				t.Fatalf("ListPullRequests(%+v): %v", query, err)
+			}
+
+			ids := []string{}
+			for _, pr := range prs {
+				ids = append(ids, pr.PullRequestID)
+			}
+			return ids
+		}
+		filter := func(f models.PullRequestFilter) models.PullRequestQuery {
+			return models.PullRequestQuery{PullRequestFilter: f}
+		}
+
+		for name, tc := range map[string]struct {
+			query models.PullRequestQuery
+			want  []string
+		}{
+			"all":           {models.PullRequestQuery{}, []string{"pr1", "pr2", "pr3", "pr4"}},
+			"newest first":  {models.PullRequestQuery{Desc: true}, []string{"pr4", "pr3", "pr2", "pr1"}},
+			"team":          {filter(models.PullRequestFilter{TeamName: "frontend"}), []string{"pr2", "pr3"}},
+			"reviewer":      {filter(models.PullRequestFilter{ReviewerID: "r1"}), []string{"pr3"}},
+			"name":          {filter(models.PullRequestFilter{NameContains: "FIX"}), []string{"pr1", "pr3"}},
+			"name wildcard": {filter(models.PullRequestFilter{NameContains: "%"}), []string{"pr2"}},
+			"name literal":  {filter(models.PullRequestFilter{NameContains: "x_l"}), []string{"pr3"}},
+			"by name":       {models.PullRequestQuery{Sort: models.SortByName}, []string{"pr2", "pr4", "pr1", "pr3"}},
+		} {
+			if got := list(tc.query); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("%s: got %v, want %v", name, got, tc.want)
+			}
+		}
+
+		after := &models.PullRequestCursor{PullRequestName: "Bump deps", PullRequestID: "pr4"}
+		got := list(models.PullRequestQuery{Sort: models.SortByName, Desc: true, After: after, Limit: 1})
+		if want := []string{"pr2"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("by name descending after cursor: got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("GetOpenReviewCountsByTeam", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateTeam(t, st, "frontend")
+		mustCreateUser(t, st, "author", "backend")
+		mustCreateUser(t, st, "r1", "backend")
+		mustCreateUser(t, st, "r2", "backend")
+		mustCreateUser(t, st, "f1", "frontend")
+		mustCreatePullRequest(t, st, "pr1", "author", "r1", "f1")
+		mustCreatePullRequest(t, st, "pr2", "author", "r1", "r2")
+		mustCreatePullRequest(t, st, "pr3", "author", "r2")
+
+		if err := st.PullRequestStorage.SetPullRequestStatus(ctx, "pr3", models.StatusMerged, time.Now().UTC()); err != nil {
+			t.Fatalf("SetPullRequestStatus: %v", err)
+		}
+
+		counts, err := st.PullRequestStorage.GetOpenReviewCountsByTeam(ctx, "backend")
+		if err != nil {
+			t.Fatalf("GetOpenReviewCountsByTeam: %v", err)
+		}
+		if want := map[string]int{"author": 0, "r1": 2, "r2": 1}; !reflect.DeepEqual(counts, want) {
+			t.Fatalf("GetOpenReviewCountsByTeam: got %v, want %v", counts, want)
+		}
+	})
+
+	t.Run("GetOpenReviewsByTeam", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateTeam(t, st, "frontend")
+		mustCreateUser(t, st, "author", "backend")
+		mustCreateUser(t, st, "r1", "backend")
+		mustCreateUser(t, st, "f1", "frontend")
+		mustCreateUser(t, st, "f2", "frontend")
+		mustCreatePullRequest(t, st, "pr1", "author", "r1", "f1")
+		mustCreatePullRequest(t, st, "pr2", "author", "f2")
+		mustCreatePullRequest(t, st, "pr3", "author", "r1")
+		mustCreatePullRequest(t, st, "pr4", "author", "f1", "r1")
+
+		if err := st.PullRequestStorage.SetPullRequestStatus(ctx, "pr3", models.StatusMerged, time.Now().UTC()); err != nil {
+			t.Fatalf("SetPullRequestStatus: %v", err)
+		}
+
+		got, err := st.PullRequestStorage.GetOpenReviewsByTeam(ctx, "frontend")
+		if err != nil {
+			t.Fatalf("GetOpenReviewsByTeam: %v", err)
+		}
+
+		want := []models.ReviewAssignment{
+			{PullRequestID: "pr1", AuthorID: "author", ReviewerID: "f1", ReviewerTeam: "frontend"},
+			{PullRequestID: "pr1", AuthorID: "author", ReviewerID: "r1", ReviewerTeam: "backend"},
+			{PullRequestID: "pr2", AuthorID: "author", ReviewerID: "f2", ReviewerTeam: "frontend"},
+			{PullRequestID: "pr4", AuthorID: "author", ReviewerID: "f1", ReviewerTeam: "frontend"},
+			{PullRequestID: "pr4", AuthorID: "author", ReviewerID: "r1", ReviewerTeam: "backend"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("GetOpenReviewsByTeam: got %+v, want %+v", got, want)
+		}
+
+		got, err = st.PullRequestStorage.GetOpenReviewsByTeam(ctx, "missing")
+		if err != nil {
+			t.Fatalf("GetOpenReviewsByTeam: %v", err)
+		}
+		if got == nil || len(got) != 0 {
+			t.Fatalf("GetOpenReviewsByTeam for unknown team: got %v, want empty slice", got)
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+)
+
+func runStatsTests(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+
+	t.Run("GetUserStats", func(t *testing.T) {
+		st := newStorage(t)
+
+		mustCreateTeam(t, st, "backend")
+		mustCreateTeam(t, st, "frontend")
+		mustCreateUser(t, st, "u1", "backend")
+		mustCreateUser(t, st, "u2", "backend")
+		mustCreateUser(t, st, "u3", "backend")
+		mustCreateUser(t, st, "v1", "frontend")
+
+		mustCreatePullRequest(t, st, "pr1", "u1", "u2", "v1")
+		mustCreatePullRequest(t, st, "pr2", "u1", "u2")
+
+		if err := st.PullRequestStorage.SetPullRequestStatus(ctx, "pr2", models.StatusMerged, time.Now().UTC()); err != nil {
+			t.Fatalf("SetPullRequestStatus: %v", err)
+		}
+
+		if err := st.EventStorage.AddEvent(ctx, models.AssignmentEvent{
+			EventType:     models.EventReassign,
+			PullRequestID: "pr1",
+			UserID:        "u3",
+			ReplacementID: "v1",
+			CreatedAt:     time.Now().UTC(),
+		}); err != nil {
+			t.Fatalf("AddEvent: %v", err)
+		}
+
+		stats, err := st.StatsStorage.GetUserStats(ctx, models.StatsFilter{})
+		if err != nil {
+			t.Fatalf("GetUserStats: %v", err)
+		}
+
+		if len(stats) != 4 {
+			t.Fatalf("GetUserStats: got %d rows, want 4: %+v", len(stats), stats)
+		}
+
+		wantOrder := []string{"u1", "u2", "u3", "v1"}
+		for i, s := range stats {
+			if s.UserID != wantOrder[i] {
+				t.Fatalf("GetUserStats: row %d is %q, want %q", i, s.UserID, wantOrder[i])
+			}
+		}
+
+		assertCounts(t, "u1", stats[0].ReviewCounts, 0, 0, 0, 0, 0)
+		assertCounts(t, "u2", stats[1].ReviewCounts, 2, 1, 1, 0, 0)
+		assertCounts(t, "u3", stats[2].ReviewCounts, 0, 0, 0, 0, 1)
+		assertCounts(t, "v1", stats[3].ReviewCounts, 1, 1, 0, 1, 0)
+
+		if stats[0].AvgTimeToMergeSeconds != nil {
+			t.Fatalf("u1 avg time to merge: got %v, want nil", *stats[0].AvgTimeToMergeSeconds)
+		}
+		if avg := stats[1].AvgTimeToMergeSeconds; avg == nil || *avg < 0 {
+			t.Fatalf("u2 avg time to merge: got %v, want non-negative value", avg)
+		}
+
+		byTeam, err := st.StatsStorage.GetUserStats(ctx, models.StatsFilter{TeamName: "frontend"})
+		if err != nil {
+			t.Fatalf("GetUserStats by team: %v", err)
+		}
+		if len(byTeam) != 1 || byTeam[0].UserID != "v1" {
+			t.Fatalf("GetUserStats by team: got %+v, want only v1", byTeam)
+		}
+
+		from := time.Now().UTC().Add(time.Hour)
+		later, err := st.StatsStorage.GetUserStats(ctx, models.StatsFilter{From: &from})
+		if err != nil {
+			t.Fatalf("GetUserStats from future: %v", err)
+		}
+		for _, s := range later {
+			assertCounts(t, s.UserID+" in future window", s.ReviewCounts, 0, 0, 0, 0, 0)
+		}
+	})
+
+	t.Run("GetReviewLoad", func(t *testing.T) {
+		st := newStorage(t)
+
+		mustCreateTeam(t, st, "backend")
+		mustCreateTeam(t, st, "frontend")
+		for _, id := range []string{"a", "b", "c", "d"} {
+			mustCreateUser(t, st, id, "backend")
+		}
+		mustCreateUser(t, st, "author", "frontend")
+
+		mustCreatePullRequest(t, st, "pr1", "author", "a", "b")
+		mustCreatePullRequest(t, st, "pr2", "author", "a", "d")
+		mustCreatePullRequest(t, st, "pr3", "author", "a")
+
+		if err := st.UserStorage.SetUserActiveStatus(ctx, "d", false); err != nil {
+			t.Fatalf("SetUserActiveStatus: %v", err)
+		}
+
+		since := time.Now().UTC().Add(-time.Hour)
+		dist, err := st.StatsStorage.GetReviewLoad(ctx, "backend", since)
+		if err != nil {
+			t.Fatalf("GetReviewLoad: %v", err)
+		}
+
+		var got []string
+		for _, u := range dist.Users {
+			got = append(got, fmt.Sprintf("%s=%d", u.UserID, u.Reviews))
+		}
+		if want := []string{"a=3", "b=1", "c=0"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("GetReviewLoad users: got %v, want %v", got, want)
+		}
+
+		if dist.Members != 3 || dist.TotalReviews != 4 || dist.Min != 0 || dist.Max != 3 {
+			t.Fatalf("GetReviewLoad: got %+v, want 3 members, 4 reviews, min 0, max 3", dist)
+		}
+		assertFloat(t, "mean", dist.Mean, 4.0/3)
+		assertFloat(t, "stddev", dist.StdDev, math.Sqrt(14.0/9))
+		assertFloat(t, "gini", dist.Gini, 0.5)
+
+		later, err := st.StatsStorage.GetReviewLoad(ctx, "backend", time.Now().UTC().Add(time.Hour))
+		if err != nil {
+			t.Fatalf("GetReviewLoad from future: %v", err)
+		}
+		if later.Members != 3 || later.TotalReviews != 0 || later.Gini != 0 || later.StdDev != 0 {
+			t.Fatalf("GetReviewLoad from future: got %+v, want 3 idle members", later)
+		}
+
+		empty, err := st.StatsStorage.GetReviewLoad(ctx, "missing", since)
+		if err != nil {
+			t.Fatalf("GetReviewLoad for missing team: %v", err)
+		}
+		if empty.Members != 0 || empty.Users == nil || len(empty.Users) != 0 {
+			t.Fatalf("GetReviewLoad for missing team: got %+v, want no members", empty)
+		}
+	})
+}
+
+func assertFloat(t *testing.T, name string, got, want float64) {
+	t.Helper()
+
+	if math.Abs(got-want) > 1e-9 {
+		t.Fatalf("%s: got %v, want %v", name, got, want)
+	}
+}
+
+func assertCounts(t *testing.T, name string, got models.ReviewCounts, total, open, merged, in, out int) {
+	t.Helper()
+
+	if got.TotalAssignments != total || got.OpenReviews != open || got.MergedReviews != merged ||
+		got.ReassignedIn != in || got.ReassignedOut != out {
+		t.Fatalf("%s: got total=%d open=%d merged=%d in=%d out=%d, want %d %d %d %d %d",
+			name, got.TotalAssignments, got.OpenReviews, got.MergedReviews, got.ReassignedIn, got.ReassignedOut,
+			total, open, merged, in, out)
+	}
+}
@@ -0,0 +1,76 @@
+// Package storagetest is a conformance suite for storage.Storage
+// implementations. Backends call Run from their own tests so that every
+// backend behaves the same way towards the handlers.
+package storagetest
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+)
+
+// Factory returns an empty storage. It is called once per subtest.
+type Factory func(t *testing.T) *storage.Storage
+
+func Run(t *testing.T, newStorage Factory) {
+	t.Run("Users", func(t *testing.T) { runUserTests(t, newStorage) })
+	t.Run("Teams", func(t *testing.T) { runTeamTests(t, newStorage) })
+	t.Run("PullRequests", func(t *testing.T) { runPullRequestTests(t, newStorage) })
+	t.Run("Events", func(t *testing.T) { runEventTests(t, newStorage) })
+	t.Run("Stats", func(t *testing.T) { runStatsTests(t, newStorage) })
+	t.Run("Transactions", func(t *testing.T) { runTransactionTests(t, newStorage) })
+}
+
+func mustCreateTeam(t *testing.T, st *storage.Storage, teamName string) {
+	t.Helper()
+
+	if err := st.TeamStorage.CreateTeam(context.Background(), teamName, 2); err != nil {
+		t.Fatalf("CreateTeam(%q): %v", teamName, err)
+	}
+}
+
+func mustCreateUser(t *testing.T, st *storage.Storage, userID, teamName string) {
+	t.Helper()
+
+	if err := st.UserStorage.CreateUser(context.Background(), userID, userID, teamName); err != nil {
+		t.Fatalf("CreateUser(%q): %v", userID, err)
+	}
+}
+
+func mustCreatePullRequest(t *testing.T, st *storage.Storage, prID, authorID string, reviewers ...string) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	if err := st.PullRequestStorage.CreatePullRequest(ctx, prID, prID, authorID, models.StatusOpen); err != nil {
+		t.Fatalf("CreatePullRequest(%q): %v", prID, err)
+	}
+
+	for _, r := range reviewers {
+		if err := st.PullRequestStorage.AddReviewer(ctx, prID, r, nil); err != nil {
+			t.Fatalf("AddReviewer(%q, %q): %v", prID, r, err)
+		}
+	}
+}
+
+// sortedIDs returns user ids in ascending order, since backends do not
+// guarantee the order of listing queries.
+func sortedIDs(users []models.User) []string {
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.UserID)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+func sortedStrings(values []string) []string {
+	result := append([]string{}, values...)
+	sort.Strings(result)
+
+	return result
+}
@@ -0,0 +1,357 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
+)
+
+func runTeamTests(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+
+	t.Run("GetTeamByNameNotFound", func(t *testing.T) {
+		st := newStorage(t)
+
+		_, err := st.TeamStorage.GetTeamByName(ctx, "missing")
+		if !errors.Is(err, storageErrors.ErrTeamNotFound) {
+			t.Fatalf("got %v, want ErrTeamNotFound", err)
+		}
+	})
+
+	t.Run("CreateTeam", func(t *testing.T) {
+		st := newStorage(t)
+
+		if err := st.TeamStorage.CreateTeam(ctx, "backend", 3); err != nil {
+			t.Fatalf("CreateTeam: %v", err)
+		}
+		mustCreateUser(t, st, "u1", "backend")
+		mustCreateUser(t, st, "u2", "backend")
+
+		team, err := st.TeamStorage.GetTeamByName(ctx, "backend")
+		if err != nil {
+			t.Fatalf("GetTeamByName: %v", err)
+		}
+		if team.TeamName != "backend" || team.RequiredReviewers != 3 {
+			t.Fatalf("GetTeamByName: got %+v", team)
+		}
+		if len(team.FallbackTeams) != 0 {
+			t.Fatalf("FallbackTeams: got %v, want none", team.FallbackTeams)
+		}
+
+		ids := []string{}
+		for _, m := range team.Members {
+			if !m.IsActive {
+				t.Fatalf("member %q is not active", m.UserID)
+			}
+			ids = append(ids, m.UserID)
+		}
+		if got, want := sortedStrings(ids), []string{"u1", "u2"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("Members: got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("CreateTeamDuplicate", func(t *testing.T) {
+		st := newStorage(t)
+
+		if err := st.TeamStorage.CreateTeam(ctx, "backend", 3); err != nil {
+			t.Fatalf("CreateTeam: %v", err)
+		}
+		if err := st.TeamStorage.CreateTeam(ctx, "backend", 5); err != nil {
+			t.Fatalf("CreateTeam with existing name: %v", err)
+		}
+
+		team, err := st.TeamStorage.GetTeamByName(ctx, "backend")
+		if err != nil {
+			t.Fatalf("GetTeamByName: %v", err)
+		}
+		if team.RequiredReviewers != 3 {
+			t.Fatalf("RequiredReviewers: got %d, want the original 3", team.RequiredReviewers)
+		}
+	})
+
+	t.Run("SetRequiredReviewers", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+
+		if err := st.TeamStorage.SetRequiredReviewers(ctx, "backend", 1); err != nil {
+			t.Fatalf("SetRequiredReviewers: %v", err)
+		}
+
+		team, err := st.TeamStorage.GetTeamByName(ctx, "backend")
+		if err != nil {
+			t.Fatalf("GetTeamByName: %v", err)
+		}
+		if team.RequiredReviewers != 1 {
+			t.Fatalf("RequiredReviewers: got %d, want 1", team.RequiredReviewers)
+		}
+
+		err = st.TeamStorage.SetRequiredReviewers(ctx, "missing", 1)
+		if !errors.Is(err, storageErrors.ErrTeamNotFound) {
+			t.Fatalf("SetRequiredReviewers on missing team: got %v, want ErrTeamNotFound", err)
+		}
+	})
+
+	t.Run("SetMergePolicy", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+
+		team, err := st.TeamStorage.GetTeamByName(ctx, "backend")
+		if err != nil {
+			t.Fatalf("GetTeamByName: %v", err)
+		}
+		if team.MergePolicy != (models.MergePolicy{}) {
+			t.Fatalf("MergePolicy of a new team: got %+v, want zero", team.MergePolicy)
+		}
+
+		policy := models.MergePolicy{
+			MinApprovals:             2,
+			BlockOnChangesRequested:  true,
+			ForbidAuthorSoleApprover: true,
+			RequireAllReviewers:      true,
+		}
+		if err := st.TeamStorage.SetMergePolicy(ctx, "backend", policy); err != nil {
+			t.Fatalf("SetMergePolicy: %v", err)
+		}
+
+		team, err = st.TeamStorage.GetTeamByName(ctx, "backend")
+		if err != nil {
+			t.Fatalf("GetTeamByName: %v", err)
+		}
+		if team.MergePolicy != policy {
+			t.Fatalf("MergePolicy: got %+v, want %+v", team.MergePolicy, policy)
+		}
+
+		err = st.TeamStorage.SetMergePolicy(ctx, "missing", policy)
+		if !errors.Is(err, storageErrors.ErrTeamNotFound) {
+			t.Fatalf("SetMergePolicy on missing team: got %v, want ErrTeamNotFound", err)
+		}
+	})
+
+	t.Run("SetFallbackTeams", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateTeam(t, st, "frontend")
+		mustCreateTeam(t, st, "mobile")
+
+		if err := st.TeamStorage.SetFallbackTeams(ctx, "backend", []string{"mobile", "frontend"}); err != nil {
+			t.Fatalf("SetFallbackTeams: %v", err)
+		}
+
+		team, err := st.TeamStorage.GetTeamByName(ctx, "backend")
+		if err != nil {
+			t.Fatalf("GetTeamByName: %v", err)
+		}
+		if want := []string{"mobile", "frontend"}; !reflect.DeepEqual(team.FallbackTeams, want) {
+			t.Fatalf("FallbackTeams: got %v, want %v", team.FallbackTeams, want)
+		}
+
+		if err := st.TeamStorage.SetFallbackTeams(ctx, "backend", []string{"frontend"}); err != nil {
+			t.Fatalf("SetFallbackTeams: %v", err)
+		}
+
+		team, err = st.TeamStorage.GetTeamByName(ctx, "backend")
+		if err != nil {
+			t.Fatalf("GetTeamByName: %v", err)
+		}
+		if want := []string{"frontend"}; !reflect.DeepEqual(team.FallbackTeams, want) {
+			t.Fatalf("FallbackTeams after replace: got %v, want %v", team.FallbackTeams, want)
+		}
+
+		if err := st.TeamStorage.SetFallbackTeams(ctx, "backend", []string{"missing"}); err == nil {
+			t.Fatal("SetFallbackTeams with unknown team: got nil error")
+		}
+	})
+
+	t.Run("GetUsersByTeam", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateTeam(t, st, "frontend")
+		mustCreateUser(t, st, "u1", "backend")
+		mustCreateUser(t, st, "u2", "backend")
+		mustCreateUser(t, st, "u3", "frontend")
+
+		if err := st.UserStorage.SetUserActiveStatus(ctx, "u2", false); err != nil {
+			t.Fatalf("SetUserActiveStatus: %v", err)
+		}
+
+		users, err := st.TeamStorage.GetUsersByTeam(ctx, "backend")
+		if err != nil {
+			t.Fatalf("GetUsersByTeam: %v", err)
+		}
+		if got, want := sortedIDs(users), []string{"u1", "u2"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("GetUsersByTeam: got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("AdvanceRotation", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateTeam(t, st, "frontend")
+
+		for _, step := range []struct {
+			team string
+			by   int
+			want int64
+		}{
+			{"backend", 2, 0},
+			{"backend", 1, 2},
+			{"frontend", 1, 0},
+			{"backend", 2, 3},
+		} {
+			got, err := st.TeamStorage.AdvanceRotation(ctx, step.team, step.by)
+			if err != nil {
+				t.Fatalf("AdvanceRotation(%q, %d): %v", step.team, step.by, err)
+			}
+			if got != step.want {
+				t.Fatalf("AdvanceRotation(%q, %d): got %d, want %d", step.team, step.by, got, step.want)
+			}
+		}
+	})
+
+	t.Run("ListTeams", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "frontend")
+		mustCreateTeam(t, st, "backend")
+		mustCreateUser(t, st, "u1", "backend")
+		mustCreateUser(t, st, "u2", "backend")
+
+		if err := st.UserStorage.SetUserActiveStatus(ctx, "u2", false); err != nil {
+			t.Fatalf("SetUserActiveStatus: %v", err)
+		}
+
+		teams, err := st.TeamStorage.ListTeams(ctx)
+		if err != nil {
+			t.Fatalf("ListTeams: %v", err)
+		}
+
+		want := []models.TeamSummary{
+			{TeamName: "backend", RequiredReviewers: 2, Members: 2, ActiveMembers: 1},
+			{TeamName: "frontend", RequiredReviewers: 2},
+		}
+		if !reflect.DeepEqual(teams, want) {
+			t.Fatalf("ListTeams: got %+v, want %+v", teams, want)
+		}
+	})
+
+	t.Run("RenameTeam", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateTeam(t, st, "frontend")
+		mustCreateTeam(t, st, "platform")
+		mustCreateUser(t, st, "u1", "backend")
+
+		policy := models.MergePolicy{MinApprovals: 1, BlockOnChangesRequested: true}
+		if err := st.TeamStorage.SetMergePolicy(ctx, "backend", policy); err != nil {
+			t.Fatalf("SetMergePolicy: %v", err)
+		}
+		if err := st.TeamStorage.SetFallbackTeams(ctx, "backend", []string{"platform"}); err != nil {
+			t.Fatalf("SetFallbackTeams: %v", err)
+		}
+		if err := st.TeamStorage.SetFallbackTeams(ctx, "frontend", []string{"platform", "backend"}); err != nil {
+			t.Fatalf("SetFallbackTeams: %v", err)
+		}
+		if _, err := st.TeamStorage.AdvanceRotation(ctx, "backend", 3); err != nil {
+			t.Fatalf("AdvanceRotation: %v", err)
+		}
+
+		if err := st.TeamStorage.RenameTeam(ctx, "backend", "core"); err != nil {
+			t.Fatalf("RenameTeam: %v", err)
+		}
+
+		if _, err := st.TeamStorage.GetTeamByName(ctx, "backend"); !errors.Is(err, storageErrors.ErrTeamNotFound) {
+			t.Fatalf("GetTeamByName(old name): got %v, want ErrTeamNotFound", err)
+		}
+
+		core, err := st.TeamStorage.GetTeamByName(ctx, "core")
+		if err != nil {
+			t.Fatalf("GetTeamByName: %v", err)
+		}
+		if core.MergePolicy != policy || !reflect.DeepEqual(core.FallbackTeams, []string{"platform"}) {
+			t.Fatalf("renamed team: got %+v", core)
+		}
+
+		user, err := st.UserStorage.GetUserByID(ctx, "u1")
+		if err != nil {
+			t.Fatalf("GetUserByID: %v", err)
+		}
+		if user.TeamName != "core" {
+			t.Fatalf("member team: got %q, want core", user.TeamName)
+		}
+
+		frontend, err := st.TeamStorage.GetTeamByName(ctx, "frontend")
+		if err != nil {
+			t.Fatalf("GetTeamByName: %v", err)
+		}
+		if want := []string{"platform", "core"}; !reflect.DeepEqual(frontend.FallbackTeams, want) {
+			t.Fatalf("dependent fallbacks: got %v, want %v", frontend.FallbackTeams, want)
+		}
+
+		position, err := st.TeamStorage.AdvanceRotation(ctx, "core", 1)
+		if err != nil {
+			t.Fatalf("AdvanceRotation: %v", err)
+		}
+		if position != 3 {
+			t.Fatalf("rotation after rename: got %d, want 3", position)
+		}
+
+		if err := st.TeamStorage.RenameTeam(ctx, "missing", "other"); !errors.Is(err, storageErrors.ErrTeamNotFound) {
+			t.Fatalf("RenameTeam(missing): got %v, want ErrTeamNotFound", err)
+		}
+		if err := st.TeamStorage.RenameTeam(ctx, "core", "frontend"); err == nil {
+			t.Fatalf("RenameTeam onto an existing team: got nil error")
+		}
+	})
+
+	t.Run("DeleteTeam", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateTeam(t, st, "frontend")
+		mustCreateTeam(t, st, "platform")
+		mustCreateUser(t, st, "u1", "frontend")
+
+		if err := st.TeamStorage.SetFallbackTeams(ctx, "backend", []string{"platform"}); err != nil {
+			t.Fatalf("SetFallbackTeams: %v", err)
+		}
+		if _, err := st.TeamStorage.AdvanceRotation(ctx, "backend", 1); err != nil {
+			t.Fatalf("AdvanceRotation: %v", err)
+		}
+
+		dependents, err := st.TeamStorage.GetDependentTeams(ctx, "platform")
+		if err != nil {
+			t.Fatalf("GetDependentTeams: %v", err)
+		}
+		if want := []string{"backend"}; !reflect.DeepEqual(dependents, want) {
+			t.Fatalf("GetDependentTeams: got %v, want %v", dependents, want)
+		}
+
+		if err := st.TeamStorage.DeleteTeam(ctx, "frontend"); err == nil {
+			t.Fatalf("DeleteTeam with members: got nil error")
+		}
+		if err := st.TeamStorage.DeleteTeam(ctx, "platform"); err == nil {
+			t.Fatalf("DeleteTeam used as fallback: got nil error")
+		}
+
+		if err := st.TeamStorage.DeleteTeam(ctx, "backend"); err != nil {
+			t.Fatalf("DeleteTeam: %v", err)
+		}
+		if _, err := st.TeamStorage.GetTeamByName(ctx, "backend"); !errors.Is(err, storageErrors.ErrTeamNotFound) {
+			t.Fatalf("GetTeamByName after delete: got %v, want ErrTeamNotFound", err)
+		}
+
+		dependents, err = st.TeamStorage.GetDependentTeams(ctx, "platform")
+		if err != nil {
+			t.Fatalf("GetDependentTeams: %v", err)
+		}
+		if len(dependents) != 0 {
+			t.Fatalf("GetDependentTeams after delete: got %v, want none", dependents)
+		}
+
+		if err := st.TeamStorage.DeleteTeam(ctx, "missing"); !errors.Is(err, storageErrors.ErrTeamNotFound) {
+			t.Fatalf("DeleteTeam(missing): got %v, want ErrTeamNotFound", err)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pacahar/pr-reviewer-assignment/internal/models"
+	"github.com/pacahar/pr-reviewer-assignment/internal/storage"
+	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
+)
+
+func runTransactionTests(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+	errInjected := errors.New("injected failure")
+
+	t.Run("Commit", func(t *testing.T) {
+		st := newStorage(t)
+
+		err := st.WithTx(ctx, func(tx *storage.Storage) error {
+			return tx.TeamStorage.CreateTeam(ctx, "backend", 2)
+		})
+		if err != nil {
+			t.Fatalf("WithTx: %v", err)
+		}
+
+		if _, err := st.TeamStorage.GetTeamByName(ctx, "backend"); err != nil {
+			t.Fatalf("GetTeamByName after commit: %v", err)
+		}
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateUser(t, st, "author", "backend")
+		mustCreateUser(t, st, "r1", "backend")
+
+		err := st.WithTx(ctx, func(tx *storage.Storage) error {
+			if err := tx.PullRequestStorage.CreatePullRequest(ctx, "pr1", "pr1", "author", models.StatusOpen); err != nil {
+				return err
+			}
+			if err := tx.PullRequestStorage.AddReviewer(ctx, "pr1", "r1", nil); err != nil {
+				return err
+			}
+			return errInjected
+		})
+		if !errors.Is(err, errInjected) {
+			t.Fatalf("WithTx: got %v, want injected error", err)
+		}
+
+		_, err = st.PullRequestStorage.GetPullRequestByID(ctx, "pr1")
+		if !errors.Is(err, storageErrors.ErrPRNotFound) {
+			t.Fatalf("GetPullRequestByID after rollback: got %v, want ErrPRNotFound", err)
+		}
+
+		prs, err := st.PullRequestStorage.GetPullRequestsByReviewer(ctx, "r1", models.PullRequestQuery{})
+		if err != nil {
+			t.Fatalf("GetPullRequestsByReviewer: %v", err)
+		}
+		if len(prs) != 0 {
+			t.Fatalf("reviewer rows survived rollback: %v", prs)
+		}
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		st := newStorage(t)
+
+		err := st.WithTx(ctx, func(tx *storage.Storage) error {
+			if err := tx.TeamStorage.CreateTeam(ctx, "backend", 2); err != nil {
+				return err
+			}
+
+			return tx.WithTx(ctx, func(inner *storage.Storage) error {
+				if _, err := inner.TeamStorage.GetTeamByName(ctx, "backend"); err != nil {
+					return err
+				}
+				return errInjected
+			})
+		})
+		if !errors.Is(err, errInjected) {
+			t.Fatalf("WithTx: got %v, want injected error", err)
+		}
+
+		_, err = st.TeamStorage.GetTeamByName(ctx, "backend")
+		if !errors.Is(err, storageErrors.ErrTeamNotFound) {
+			t.Fatalf("GetTeamByName after rollback: got %v, want ErrTeamNotFound", err)
+		}
+	})
+}
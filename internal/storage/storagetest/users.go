@@ -0,0 +1,212 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	storageErrors "github.com/pacahar/pr-reviewer-assignment/internal/storage/errors"
+)
+
+func runUserTests(t *testing.T, newStorage Factory) {
+	ctx := context.Background()
+
+	t.Run("GetUserByIDNotFound", func(t *testing.T) {
+		st := newStorage(t)
+
+		_, err := st.UserStorage.GetUserByID(ctx, "missing")
+		if !errors.Is(err, storageErrors.ErrUserNotFound) {
+			t.Fatalf("got %v, want ErrUserNotFound", err)
+		}
+	})
+
+	t.Run("CreateUser", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+
+		if err := st.UserStorage.CreateUser(ctx, "u1", "Alice", "backend"); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+
+		user, err := st.UserStorage.GetUserByID(ctx, "u1")
+		if err != nil {
+			t.Fatalf("GetUserByID: %v", err)
+		}
+		if user.UserID != "u1" || user.Username != "Alice" || user.TeamName != "backend" || !user.IsActive {
+			t.Fatalf("GetUserByID: got %+v", user)
+		}
+	})
+
+	t.Run("CreateUserDuplicate", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateUser(t, st, "u1", "backend")
+
+		if err := st.UserStorage.CreateUser(ctx, "u1", "Again", "backend"); err == nil {
+			t.Fatal("CreateUser with duplicate id: got nil error")
+		}
+	})
+
+	t.Run("CreateUserUnknownTeam", func(t *testing.T) {
+		st := newStorage(t)
+
+		if err := st.UserStorage.CreateUser(ctx, "u1", "Alice", "missing"); err == nil {
+			t.Fatal("CreateUser with unknown team: got nil error")
+		}
+	})
+
+	t.Run("SetUserActiveStatus", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateUser(t, st, "u1", "backend")
+
+		if err := st.UserStorage.SetUserActiveStatus(ctx, "u1", false); err != nil {
+			t.Fatalf("SetUserActiveStatus: %v", err)
+		}
+
+		user, err := st.UserStorage.GetUserByID(ctx, "u1")
+		if err != nil {
+			t.Fatalf("GetUserByID: %v", err)
+		}
+		if user.IsActive {
+			t.Fatal("user is still active")
+		}
+
+		if err := st.UserStorage.SetUserActiveStatus(ctx, "missing", false); err != nil {
+			t.Fatalf("SetUserActiveStatus on missing user: %v", err)
+		}
+	})
+
+	t.Run("SetTeamActiveStatus", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateTeam(t, st, "frontend")
+		mustCreateUser(t, st, "u2", "backend")
+		mustCreateUser(t, st, "u1", "backend")
+		mustCreateUser(t, st, "u3", "backend")
+		mustCreateUser(t, st, "f1", "frontend")
+
+		if err := st.UserStorage.SetUserActiveStatus(ctx, "u3", false); err != nil {
+			t.Fatalf("SetUserActiveStatus: %v", err)
+		}
+
+		changed, err := st.UserStorage.SetTeamActiveStatus(ctx, "backend", false)
+		if err != nil {
+			t.Fatalf("SetTeamActiveStatus: %v", err)
+		}
+		if want := []string{"u1", "u2"}; !reflect.DeepEqual(changed, want) {
+			t.Fatalf("SetTeamActiveStatus: got %v, want %v", changed, want)
+		}
+
+		active, err := st.UserStorage.GetActiveUsersByTeam(ctx, "backend")
+		if err != nil {
+			t.Fatalf("GetActiveUsersByTeam: %v", err)
+		}
+		if len(active) != 0 {
+			t.Fatalf("GetActiveUsersByTeam: got %v, want none", active)
+		}
+
+		f1, err := st.UserStorage.GetUserByID(ctx, "f1")
+		if err != nil {
+			t.Fatalf("GetUserByID: %v", err)
+		}
+		if !f1.IsActive {
+			t.Fatal("SetTeamActiveStatus changed a user of another team")
+		}
+
+		changed, err = st.UserStorage.SetTeamActiveStatus(ctx, "backend", false)
+		if err != nil {
+			t.Fatalf("SetTeamActiveStatus: %v", err)
+		}
+		if changed == nil || len(changed) != 0 {
+			t.Fatalf("SetTeamActiveStatus without changes: got %v, want empty slice", changed)
+		}
+	})
+
+	t.Run("SetUserTeam", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateTeam(t, st, "frontend")
+		mustCreateUser(t, st, "u1", "backend")
+
+		if err := st.UserStorage.SetUserTeam(ctx, "u1", "frontend"); err != nil {
+			t.Fatalf("SetUserTeam: %v", err)
+		}
+
+		user, err := st.UserStorage.GetUserByID(ctx, "u1")
+		if err != nil {
+			t.Fatalf("GetUserByID: %v", err)
+		}
+		if user.TeamName != "frontend" {
+			t.Fatalf("TeamName: got %q, want frontend", user.TeamName)
+		}
+
+		if err := st.UserStorage.SetUserTeam(ctx, "u1", "missing"); err == nil {
+			t.Fatal("SetUserTeam with unknown team: got nil error")
+		}
+	})
+
+	t.Run("GetActiveUsersByTeam", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateTeam(t, st, "frontend")
+		mustCreateUser(t, st, "u1", "backend")
+		mustCreateUser(t, st, "u2", "backend")
+		mustCreateUser(t, st, "u3", "backend")
+		mustCreateUser(t, st, "u4", "frontend")
+
+		if err := st.UserStorage.SetUserActiveStatus(ctx, "u2", false); err != nil {
+			t.Fatalf("SetUserActiveStatus: %v", err)
+		}
+
+		users, err := st.UserStorage.GetActiveUsersByTeam(ctx, "backend")
+		if err != nil {
+			t.Fatalf("GetActiveUsersByTeam: %v", err)
+		}
+		if got, want := sortedIDs(users), []string{"u1", "u3"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("GetActiveUsersByTeam: got %v, want %v", got, want)
+		}
+
+		users, err = st.UserStorage.GetActiveUsersByTeam(ctx, "missing")
+		if err != nil {
+			t.Fatalf("GetActiveUsersByTeam on missing team: %v", err)
+		}
+		if len(users) != 0 {
+			t.Fatalf("GetActiveUsersByTeam on missing team: got %v", users)
+		}
+	})
+
+	t.Run("DeleteUser", func(t *testing.T) {
+		st := newStorage(t)
+		mustCreateTeam(t, st, "backend")
+		mustCreateUser(t, st, "author", "backend")
+		mustCreateUser(t, st, "reviewer", "backend")
+		mustCreateUser(t, st, "idle", "backend")
+		mustCreatePullRequest(t, st, "pr1", "author", "reviewer")
+
+		for _, id := range []string{"author", "reviewer", "idle"} {
+			count, err := st.PullRequestStorage.CountPullRequestsByUser(ctx, id)
+			if err != nil {
+				t.Fatalf("CountPullRequestsByUser(%q): %v", id, err)
+			}
+			if want := map[string]int{"author": 1, "reviewer": 1}[id]; count != want {
+				t.Fatalf("CountPullRequestsByUser(%q): got %d, want %d", id, count, want)
+			}
+		}
+
+		if err := st.UserStorage.DeleteUser(ctx, "reviewer"); err == nil {
+			t.Fatalf("DeleteUser of a reviewer: got nil error")
+		}
+
+		if err := st.UserStorage.DeleteUser(ctx, "idle"); err != nil {
+			t.Fatalf("DeleteUser: %v", err)
+		}
+		if _, err := st.UserStorage.GetUserByID(ctx, "idle"); !errors.Is(err, storageErrors.ErrUserNotFound) {
+			t.Fatalf("GetUserByID after delete: got %v, want ErrUserNotFound", err)
+		}
+		if err := st.UserStorage.DeleteUser(ctx, "idle"); !errors.Is(err, storageErrors.ErrUserNotFound) {
+			t.Fatalf("DeleteUser(missing): got %v, want ErrUserNotFound", err)
+		}
+	})
+}
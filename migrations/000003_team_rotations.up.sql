@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS team_rotations (
+    team_name TEXT PRIMARY KEY REFERENCES teams(team_name),
+    position BIGINT NOT NULL DEFAULT 0
+);
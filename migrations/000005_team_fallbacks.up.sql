@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS team_fallbacks (
+    team_name TEXT NOT NULL REFERENCES teams(team_name),
+    fallback_team_name TEXT NOT NULL REFERENCES teams(team_name),
+    priority INT NOT NULL,
+    PRIMARY KEY (team_name, fallback_team_name)
+);
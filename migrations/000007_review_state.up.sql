@@ -0,0 +1,5 @@
+ALTER TABLE pr_reviewers
+    ADD COLUMN IF NOT EXISTS review_state TEXT NOT NULL DEFAULT 'PENDING'
+        CONSTRAINT pr_reviewers_review_state_check
+        CHECK (review_state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
@@ -0,0 +1,5 @@
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS require_all_reviewers,
+    DROP COLUMN IF EXISTS forbid_author_sole_approver,
+    DROP COLUMN IF EXISTS block_on_changes_requested,
+    DROP COLUMN IF EXISTS min_approvals;
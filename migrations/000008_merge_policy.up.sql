@@ -0,0 +1,5 @@
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS min_approvals INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS block_on_changes_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS forbid_author_sole_approver BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS require_all_reviewers BOOLEAN NOT NULL DEFAULT FALSE;
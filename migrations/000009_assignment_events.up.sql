@@ -0,0 +1,17 @@
+-- Audit log. IDs are kept without foreign keys so history outlives the rows
+-- it refers to.
+CREATE TABLE IF NOT EXISTS assignment_events (
+    event_id BIGSERIAL PRIMARY KEY,
+    event_type TEXT NOT NULL
+        CHECK (event_type IN ('ASSIGN', 'REASSIGN', 'MERGE', 'ACTIVATE', 'DEACTIVATE')),
+    pull_request_id TEXT,
+    user_id TEXT,
+    replacement_id TEXT,
+    actor TEXT,
+    reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS assignment_events_pull_request_idx ON assignment_events (pull_request_id, event_id);
+CREATE INDEX IF NOT EXISTS assignment_events_user_idx ON assignment_events (user_id, event_id);
+CREATE INDEX IF NOT EXISTS assignment_events_replacement_idx ON assignment_events (replacement_id, event_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS pr_reviewers_reviewer_idx;
+DROP INDEX IF EXISTS users_team_name_idx;
@@ -0,0 +1,6 @@
+-- pg_trgm is left installed, other objects may depend on it.
+DROP INDEX IF EXISTS pull_requests_name_trgm_idx;
+DROP INDEX IF EXISTS pull_requests_name_idx;
+DROP INDEX IF EXISTS pull_requests_author_created_at_idx;
+DROP INDEX IF EXISTS pull_requests_status_created_at_idx;
+DROP INDEX IF EXISTS pull_requests_created_at_idx;
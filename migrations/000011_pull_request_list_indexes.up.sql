@@ -0,0 +1,9 @@
+CREATE INDEX IF NOT EXISTS pull_requests_created_at_idx ON pull_requests (created_at, pull_request_id);
+CREATE INDEX IF NOT EXISTS pull_requests_status_created_at_idx ON pull_requests (status, created_at, pull_request_id);
+CREATE INDEX IF NOT EXISTS pull_requests_author_created_at_idx ON pull_requests (author_id, created_at, pull_request_id);
+-- Serves sort=name only; the name filter is a substring ILIKE, which a btree
+-- cannot answer and the trigram index below does.
+CREATE INDEX IF NOT EXISTS pull_requests_name_idx ON pull_requests (pull_request_name COLLATE "C", pull_request_id);
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS pull_requests_name_trgm_idx ON pull_requests USING GIN (pull_request_name gin_trgm_ops);
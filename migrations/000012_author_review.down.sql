@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS author_reviewed_at,
+    DROP COLUMN IF EXISTS author_review_state;
@@ -0,0 +1,5 @@
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS author_review_state TEXT
+        CONSTRAINT pull_requests_author_review_state_check
+        CHECK (author_review_state IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    ADD COLUMN IF NOT EXISTS author_reviewed_at TIMESTAMPTZ;
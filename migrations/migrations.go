@@ -0,0 +1,8 @@
+// Package migrations embeds the numbered Postgres schema migrations. Files are
+// named NNNNNN_name.up.sql and NNNNNN_name.down.sql.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS